@@ -5,11 +5,16 @@ import (
 	"bytes"
 	_ "embed"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"image"
 	"image/png"
 	"io"
+	"io/fs"
 	"log"
+	"strings"
+	"sync"
+	"unicode"
 
 	"github.com/golang/freetype/truetype"
 	"golang.org/x/image/font"
@@ -25,14 +30,16 @@ const defaultObjectScale = 1.0 / 200.0
 const arcImagePath = "xcircle_aoe.png"
 
 type Asset struct {
-	ID    int         `json:"id"`
+	ID    ObjectType  `json:"id"`
 	Name  string      `json:"name"`
 	Scale float64     `json:"scale"`
 	Image image.Image `json:"-"`
 }
 
+/* Assets loaded once and shared by every board, guarded by assetCacheMutex so boards can be drawn concurrently. */
+var assetCacheMutex sync.Mutex
 var assetList []Asset
-var boardFont font.Face
+var boardFont *truetype.Font
 var arcImage image.Image
 
 /* Read asset zip archive stored as go embed */
@@ -61,6 +68,8 @@ func loadAsset(zr *zip.Reader, name string) ([]byte, error) {
 
 /* Load asset list from zip reader */
 func loadAssetList(zr *zip.Reader) ([]Asset, error) {
+	assetCacheMutex.Lock()
+	defer assetCacheMutex.Unlock()
 	if assetList != nil {
 		return assetList, nil
 	}
@@ -68,40 +77,104 @@ func loadAssetList(zr *zip.Reader) ([]Asset, error) {
 	if err != nil {
 		return nil, err
 	}
-	assetList = make([]Asset, 0)
-	if err := json.Unmarshal(data, &assetList); err != nil {
+	assets := make([]Asset, 0)
+	if err := json.Unmarshal(data, &assets); err != nil {
 		return nil, err
 	}
-	for i := range assetList {
-		if assetList[i].Scale == 0 {
-			assetList[i].Scale = defaultObjectScale
+	for i := range assets {
+		if assets[i].Scale == 0 {
+			assets[i].Scale = defaultObjectScale
 		}
 	}
+	assetList = assets
 	return assetList, nil
 }
 
-/* Load font used for text in strategy board */
-func loadFont(zr *zip.Reader) (font.Face, error) {
-	if boardFont != nil {
-		return boardFont, nil
+/* Check object type is a special object or has an asset */
+func isKnownObjectType(typeId ObjectType) (bool, error) {
+	if _, ok := objectTypeNames[typeId]; ok {
+		return true, nil
 	}
-	var err error
-	if zr == nil {
-		zr, err = loadAssetsZip()
-		if err != nil {
-			return nil, err
+	assets, err := loadAssetList(nil)
+	if err != nil {
+		return false, err
+	}
+	for _, asset := range assets {
+		if asset.ID == typeId {
+			return true, nil
 		}
 	}
-	fontBytes, err := loadAsset(zr, assetFontPath)
-	if err != nil {
-		return nil, err
+	return false, nil
+}
+
+/* Name of object type, from the hand written or generated names */
+func objectTypeLabel(typeId ObjectType) string {
+	if name, ok := objectTypeNames[typeId]; ok {
+		return name
 	}
-	font, err := truetype.Parse(fontBytes)
+	return spriteObjectTypeNames[typeId]
+}
+
+/* Lowercase object type name with anything but letters and digits removed */
+func normalizeObjectTypeLabel(name string) string {
+	return strings.Map(func(r rune) rune {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, name)
+}
+
+/* Find object type by its name, ignoring case, spaces and punctuation */
+func objectTypeByLabel(name string) (ObjectType, bool, error) {
+	name = normalizeObjectTypeLabel(name)
+	for typeId, typeName := range objectTypeNames {
+		if normalizeObjectTypeLabel(typeName) == name {
+			return typeId, true, nil
+		}
+	}
+	assets, err := loadAssetList(nil)
 	if err != nil {
-		return nil, err
+		return 0, false, err
+	}
+	for _, asset := range assets {
+		if normalizeObjectTypeLabel(asset.Name) == name {
+			return asset.ID, true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+/* Load font used for text in strategy board */
+func loadFont(zr *zip.Reader) (font.Face, error) {
+	return loadFontFace(zr, assetFontSize)
+}
+
+/*
+Load font used for text in strategy board at a given size. The font is parsed once but every call gets a
+face of its own, faces cache glyphs as they're drawn and can't be shared between goroutines.
+*/
+func loadFontFace(zr *zip.Reader, size float64) (font.Face, error) {
+	assetCacheMutex.Lock()
+	defer assetCacheMutex.Unlock()
+	if boardFont == nil {
+		var err error
+		if zr == nil {
+			zr, err = loadAssetsZip()
+			if err != nil {
+				return nil, err
+			}
+		}
+		fontBytes, err := loadAsset(zr, assetFontPath)
+		if err != nil {
+			return nil, err
+		}
+		boardFont, err = truetype.Parse(fontBytes)
+		if err != nil {
+			return nil, err
+		}
 	}
-	boardFont = truetype.NewFace(font, &truetype.Options{Size: assetFontSize})
-	return boardFont, nil
+	return truetype.NewFace(boardFont, &truetype.Options{Size: size}), nil
 }
 
 /* Load image from zip reader */
@@ -131,23 +204,35 @@ func (a *Asset) loadAssetImage(zr *zip.Reader) error {
 
 /* Load background image from zip read */
 func loadBackgroundImage(zr *zip.Reader, id int) (image.Image, error) {
-	return loadImage(zr, fmt.Sprintf("x%d.png", id))
+	background, err := loadImage(zr, fmt.Sprintf("x%d.png", id))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: background %d", AssetNotFound, id)
+	}
+	return background, err
 }
 
 /* Load arc image, aka circle aoe, used by a few objects */
 func loadArcImage(zr *zip.Reader) (image.Image, error) {
+	assetCacheMutex.Lock()
+	defer assetCacheMutex.Unlock()
 	if arcImage != nil {
 		return arcImage, nil
 	}
-	var err error
-	arcImage, err = loadImage(zr, arcImagePath)
-	return arcImage, err
+	loaded, err := loadImage(zr, arcImagePath)
+	if err != nil {
+		return nil, err
+	}
+	arcImage = loaded
+	return arcImage, nil
 }
 
 /* Load assets needed by given strategy board */
 func (b Board) Assets() ([]Asset, error) {
 	// load asset data
 	zr, err := loadAssetsZip()
+	if err != nil {
+		return nil, err
+	}
 	assets, err := loadAssetList(zr)
 	if err != nil {
 		return nil, err
@@ -178,6 +263,9 @@ func (b Board) Assets() ([]Asset, error) {
 
 	// load background image as special asset (ID: -1)
 	bgImage, err := loadBackgroundImage(zr, b.Background)
+	if err != nil {
+		return nil, err
+	}
 	boardAssets = append(boardAssets, Asset{Name: "Background", ID: -1, Image: bgImage})
 
 	// preload additional assets
@@ -2,20 +2,71 @@ package strategy_board
 
 import (
 	"image/color"
+	"math"
 )
 
+/* Size of the board in native coordinate units, as stored in share codes. */
+const BoardWidth = 5120
+const BoardHeight = 3840
+
+/* Convert native board coordinates to a position on a canvas of the given size. */
+func NativeToCanvas(x int, y int, width int, height int) (float64, float64) {
+	return float64(x) / BoardWidth * float64(width), float64(y) / BoardHeight * float64(height)
+}
+
+/* Convert a position on a canvas of the given size to native board coordinates. */
+func CanvasToNative(x float64, y float64, width int, height int) (int, int) {
+	return int(math.Round(x / float64(width) * BoardWidth)), int(math.Round(y / float64(height) * BoardHeight))
+}
+
+/* Strategy board object, X and Y are in native board coordinates. */
 type Object struct {
-	TypeID         int         `json:"type_id"`
-	Text           string      `json:"text"`
-	Visible        bool        `json:"visible"`
-	FlipHorizontal bool        `json:"flip_horizontal"`
-	FlipVertical   bool        `json:"flip_vertical"`
-	X              int         `json:"x"`
-	Y              int         `json:"y"`
-	Angle          int         `json:"angle"`
-	Color          color.NRGBA `json:"color"`
-	Scale          int         `json:"scale"`
-	Params         []int       `json:"params"`
+	TypeID         ObjectType      `json:"type_id"`
+	Text           string          `json:"text"`
+	Visible        bool            `json:"visible"`
+	FlipHorizontal bool            `json:"flip_horizontal"`
+	FlipVertical   bool            `json:"flip_vertical"`
+	Locked         bool            `json:"locked"`
+	Flags          BoardObjectFlag `json:"flags"`
+	X              int             `json:"x"`
+	Y              int             `json:"y"`
+	Angle          int             `json:"angle"`
+	Color          color.NRGBA     `json:"color"`
+	Scale          int             `json:"scale"`
+	Params         []int           `json:"params"`
+}
+
+/* Raw flag word with the known flags taken from the object's fields and any unknown bits kept as is. */
+func (o Object) RawFlags() BoardObjectFlag {
+	flags := o.Flags &^ knownObjectFlags
+	if o.Visible {
+		flags |= Visible
+	}
+	if o.FlipHorizontal {
+		flags |= FlipHorizontal
+	}
+	if o.FlipVertical {
+		flags |= FlipVertical
+	}
+	if o.Locked {
+		flags |= Locked
+	}
+	return flags
+}
+
+/* Position of object on a canvas of the given size. */
+func (o Object) CanvasPosition(width int, height int) (float64, float64) {
+	return NativeToCanvas(o.X, o.Y, width, height)
+}
+
+/* Position of object in pixels on the default 1024x768 canvas. */
+func (o Object) PixelPosition() (float64, float64) {
+	return o.CanvasPosition(canvasWidth, canvasHeight)
+}
+
+/* Move object to a position on the default 1024x768 canvas. */
+func (o *Object) SetPixelPosition(x float64, y float64) {
+	o.X, o.Y = CanvasToNative(x, y, canvasWidth, canvasHeight)
 }
 
 func (o Object) ScaleFactor(factor float64) (float64, float64) {
@@ -31,8 +82,61 @@ func (o Object) ScaleFactor(factor float64) (float64, float64) {
 	return scale * flipH, scale * flipV
 }
 
+/* Regions of board data that aren't decoded, kept so a parsed board re-encodes to the same bytes. */
+type RawBoardData struct {
+	Header           []byte         `json:"header"`
+	SectionHeaders   map[int][]byte `json:"section_headers"`
+	ScalePadding     []byte         `json:"scale_padding"`
+	BackgroundHeader []byte         `json:"background_header"`
+	Trailer          []byte         `json:"trailer"`
+}
+
+/* Return raw bytes if they have the expected length, otherwise zeros. */
+func rawBytesOrZero(raw []byte, length int) []byte {
+	if len(raw) == length {
+		return raw
+	}
+	return make([]byte, length)
+}
+
+func (r *RawBoardData) header() []byte {
+	if r == nil {
+		return make([]byte, headerLength)
+	}
+	return rawBytesOrZero(r.Header, headerLength)
+}
+
+func (r *RawBoardData) sectionHeader(sectionNumber int) []byte {
+	if r == nil {
+		return make([]byte, 2)
+	}
+	return rawBytesOrZero(r.SectionHeaders[sectionNumber], 2)
+}
+
+func (r *RawBoardData) scalePadding(objectCount int) []byte {
+	if r == nil {
+		return make([]byte, objectCount%2)
+	}
+	return rawBytesOrZero(r.ScalePadding, objectCount%2)
+}
+
+func (r *RawBoardData) backgroundHeader() []byte {
+	if r == nil {
+		return make([]byte, 4)
+	}
+	return rawBytesOrZero(r.BackgroundHeader, 4)
+}
+
+func (r *RawBoardData) trailer() []byte {
+	if r == nil {
+		return nil
+	}
+	return r.Trailer
+}
+
 type Board struct {
-	Name       string   `json:"name"`
-	Background int      `json:"background"`
-	Objects    []Object `json:"object"`
+	Name       string        `json:"name"`
+	Background int           `json:"background"`
+	Objects    []Object      `json:"objects"`
+	Raw        *RawBoardData `json:"raw,omitempty"`
 }
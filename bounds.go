@@ -0,0 +1,171 @@
+package strategy_board
+
+import (
+	"math"
+
+	"github.com/fogleman/gg"
+	"golang.org/x/image/font"
+)
+
+/* Axis aligned rectangle on the default 1024x768 canvas. */
+type Rect struct {
+	MinX float64 `json:"min_x"`
+	MinY float64 `json:"min_y"`
+	MaxX float64 `json:"max_x"`
+	MaxY float64 `json:"max_y"`
+}
+
+func (r Rect) Width() float64 {
+	return r.MaxX - r.MinX
+}
+
+func (r Rect) Height() float64 {
+	return r.MaxY - r.MinY
+}
+
+func (r Rect) Empty() bool {
+	return r.MinX >= r.MaxX || r.MinY >= r.MaxY
+}
+
+func (r Rect) Contains(x float64, y float64) bool {
+	return !r.Empty() && x >= r.MinX && x <= r.MaxX && y >= r.MinY && y <= r.MaxY
+}
+
+/* Smallest rectangle containing both rectangles, empty rectangles are ignored. */
+func (r Rect) Union(other Rect) Rect {
+	if r.Empty() {
+		return other
+	}
+	if other.Empty() {
+		return r
+	}
+	return Rect{min(r.MinX, other.MinX), min(r.MinY, other.MinY), max(r.MaxX, other.MaxX), max(r.MaxY, other.MaxY)}
+}
+
+/* Smallest rectangle containing every point, given as x, y pairs. */
+func rectAround(points ...[2]float64) Rect {
+	r := Rect{math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)}
+	for _, p := range points {
+		r.MinX, r.MinY = min(r.MinX, p[0]), min(r.MinY, p[1])
+		r.MaxX, r.MaxY = max(r.MaxX, p[0]), max(r.MaxY, p[1])
+	}
+	return r
+}
+
+/*
+Area of the canvas the object is drawn on, whether or not it's visible. Assets are the ones returned by
+Board.Assets, an object drawn from an asset image fails with AssetNotFound if its asset isn't among them.
+Text with no text, which isn't drawn, has empty bounds.
+*/
+func (o Object) Bounds(assets []Asset) (Rect, error) {
+	switch o.TypeID {
+	case ObjectTypeCircleAoE, ObjectTypeDonut:
+		return o.arcBounds()
+	case ObjectTypeLineAoE:
+		params, err := o.LineAoE()
+		if err != nil {
+			return Rect{}, err
+		}
+		m := gg.Identity().Translate(o.PixelPosition()).Rotate(gg.Radians(float64(o.Angle)))
+		w, h := float64(params.HalfWidth), float64(params.HalfHeight)
+		return transformedRect(m, w, h), nil
+	case ObjectTypeLine:
+		params, err := o.Line()
+		if err != nil {
+			return Rect{}, err
+		}
+		// stroke and endpoint dots both reach thickness past the endpoints
+		t := float64(params.Thickness)
+		return rectAround(
+			[2]float64{params.X1 - t, params.Y1 - t}, [2]float64{params.X1 + t, params.Y1 + t},
+			[2]float64{params.X2 - t, params.Y2 - t}, [2]float64{params.X2 + t, params.Y2 + t},
+		), nil
+	case ObjectTypeText:
+		return o.textBounds()
+	}
+	for _, asset := range assets {
+		if asset.ID == o.TypeID && asset.Image != nil {
+			m := gg.Identity().Translate(o.PixelPosition()).Scale(o.ScaleFactor(asset.Scale)).Rotate(gg.Radians(float64(o.Angle)))
+			size := asset.Image.Bounds().Size()
+			return transformedRect(m, float64(size.X)/2, float64(size.Y)/2), nil
+		}
+	}
+	return Rect{}, AssetNotFound
+}
+
+/* Bounds of a rectangle centered on the origin with the given half width and height after transforming it. */
+func transformedRect(m gg.Matrix, w float64, h float64) Rect {
+	points := make([][2]float64, 0, 4)
+	for _, corner := range [][2]float64{{-w, -h}, {w, -h}, {w, h}, {-w, h}} {
+		x, y := m.TransformPoint(corner[0], corner[1])
+		points = append(points, [2]float64{x, y})
+	}
+	return rectAround(points...)
+}
+
+/* Bounds of an arc, its outline is sampled every degree along with both of its ends. */
+func (o Object) arcBounds() (Rect, error) {
+	shape, err := o.arcShape()
+	if err != nil {
+		return Rect{}, err
+	}
+	m := o.arcMatrix(shape)
+	points := make([][2]float64, 0)
+	addArc := func(radius float64) {
+		steps := int(math.Ceil((shape.endAngle - shape.startAngle) / gg.Radians(1)))
+		for i := 0; i <= steps; i++ {
+			angle := min(shape.startAngle+float64(i)*gg.Radians(1), shape.endAngle)
+			x, y := m.TransformPoint(radius*math.Cos(angle), radius*math.Sin(angle))
+			points = append(points, [2]float64{x, y})
+		}
+	}
+	addArc(shape.outerRadius)
+	addArc(shape.innerRadius)
+	return rectAround(points...), nil
+}
+
+/* Bounds of text along with its drop shadow, as drawn by drawTextObject. */
+func (o Object) textBounds() (Rect, error) {
+	if o.Text == "" {
+		return Rect{}, nil
+	}
+	fontFace, err := loadFont(nil)
+	if err != nil {
+		return Rect{}, err
+	}
+	metrics := fontFace.Metrics()
+	w := float64(font.MeasureString(fontFace, o.Text) >> 6)
+	h := float64(metrics.Height) / 64
+	x, y := o.PixelPosition()
+
+	// text is anchored on its center with its baseline half the font height below it
+	baseline := y + h/2
+	shadow := Rect{x - w/2, baseline - float64(metrics.Ascent)/64, x + w/2, baseline + float64(metrics.Descent)/64}
+	text := Rect{shadow.MinX - 2, shadow.MinY - 2, shadow.MaxX - 2, shadow.MaxY - 2}
+	return shadow.Union(text), nil
+}
+
+/*
+Indexes of visible objects drawn at a point on the default 1024x768 canvas, in z-order with the
+topmost object first. Objects are hit anywhere within their bounds.
+*/
+func (b Board) ObjectsAt(x float64, y float64) ([]int, error) {
+	assets, err := b.Assets()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]int, 0)
+	for i, object := range b.Objects {
+		if !object.Visible {
+			continue
+		}
+		bounds, err := object.Bounds(assets)
+		if err != nil {
+			return nil, err
+		}
+		if bounds.Contains(x, y) {
+			out = append(out, i)
+		}
+	}
+	return out, nil
+}
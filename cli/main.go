@@ -2,10 +2,13 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"flag"
+	"fmt"
 	"image/jpeg"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 
 	strategy_board "github.com/chompy/ffxiv_strat_board_viewer"
@@ -13,10 +16,39 @@ import (
 
 func main() {
 
+	// subcommands
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "diff":
+			runDiff(os.Args[2:])
+			return
+		case "lint":
+			runLint(os.Args[2:])
+			return
+		}
+	}
+
 	// parse input args
-	input := flag.String("input", "", "strategy board share code")
-	output := flag.String("output", "image", "format to output strategy board as (json, png, jpeg)")
+	input := flag.String("input", "", "strategy board share code, or text containing share codes")
+	inputFormat := flag.String("format", "auto", "format of input (auto, code, json, dsl)")
+	output := flag.String("output", "image", "format to output strategy board as (json, dsl, code, hash, png, jpeg, svg)")
+	outputDir := flag.String("dir", "", "directory to write one file per board to when input contains several share codes")
+	drawOptions := strategy_board.DefaultDrawOptions
+	flag.IntVar(&drawOptions.Width, "width", 0, "width of image output in pixels")
+	flag.IntVar(&drawOptions.Height, "height", 0, "height of image output in pixels")
+	flag.Float64Var(&drawOptions.Scale, "scale", drawOptions.Scale, "scale of image output relative to 1024x768, when width and height aren't set")
+	flag.IntVar(&drawOptions.Supersample, "supersample", drawOptions.Supersample, "draw images at this many times their size and scale down for smoother edges")
+	flag.BoolVar(&drawOptions.ShowHidden, "show-hidden", false, "draw hidden objects faded with a dashed outline")
+	flag.BoolVar(&drawOptions.HideBackground, "hide-background", false, "leave out the background image, the image is transparent unless -background-color is set")
+	backgroundColor := flag.String("background-color", "", "color to fill the image with behind the background, as #RRGGBB or #RRGGBBAA")
 	flag.Parse()
+	if *backgroundColor != "" {
+		var err error
+		drawOptions.BackgroundColor, err = strategy_board.ParseHexColor(*backgroundColor)
+		if err != nil {
+			panic(err)
+		}
+	}
 	if *input == "" {
 		stat, _ := os.Stdin.Stat()
 		if (stat.Mode() & os.ModeCharDevice) == 0 {
@@ -28,46 +60,257 @@ func main() {
 		}
 	}
 
-	// load board
-	board, err := strategy_board.Load(strings.TrimSpace(*input))
+	boards, err := loadBoards(*input, *inputFormat)
 	if err != nil {
 		panic(err)
 	}
 
+	// single board, output to stdout
+	if len(boards) == 1 {
+		if err := writeBoard(boards[0], *output, drawOptions, os.Stdout); err != nil {
+			panic(err)
+		}
+		return
+	}
+
+	// several boards, output json as array or one file per board
+	if *output == "json" && *outputDir == "" {
+		out, err := json.Marshal(boards)
+		if err != nil {
+			panic(err)
+		}
+		os.Stdout.Write(out)
+		return
+	}
+	if *outputDir == "" {
+		panic(errors.New("input contains several share codes, use -dir to render each of them"))
+	}
+	for i, board := range boards {
+		f, err := os.Create(filepath.Join(*outputDir, fmt.Sprintf("board-%d.%s", i+1, fileExtension(*output))))
+		if err != nil {
+			panic(err)
+		}
+		if err := writeBoard(board, *output, drawOptions, f); err != nil {
+			panic(err)
+		}
+		f.Close()
+	}
+
+}
+
+/* Compare two boards, given as share codes, JSON, board description language or files containing any of them. */
+func runDiff(args []string) {
+	flags := flag.NewFlagSet("diff", flag.ExitOnError)
+	output := flags.String("output", "text", "format to output diff as (text, json, png, jpeg)")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: diff [-output format] BOARD_A BOARD_B")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 2 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	boards := make([]strategy_board.Board, 0, 2)
+	for _, arg := range flags.Args() {
+		boards = append(boards, loadBoardArg(arg))
+	}
+
+	switch *output {
+	case "text":
+		fmt.Print(strategy_board.Diff(boards[0], boards[1]))
+	case "json":
+		out, err := json.Marshal(strategy_board.Diff(boards[0], boards[1]))
+		if err != nil {
+			panic(err)
+		}
+		os.Stdout.Write(out)
+	case "image", "png", "jpeg", "jpg":
+		image, err := strategy_board.DrawDiff(boards[0], boards[1])
+		if err != nil {
+			panic(err)
+		}
+		if *output == "jpeg" || *output == "jpg" {
+			err = jpeg.Encode(os.Stdout, image.Image(), nil)
+		} else {
+			err = image.EncodePNG(os.Stdout)
+		}
+		if err != nil {
+			panic(err)
+		}
+	default:
+		panic(fmt.Errorf("unknown output format %s", *output))
+	}
+}
+
+func runLint(args []string) {
+	flags := flag.NewFlagSet("lint", flag.ExitOnError)
+	output := flags.String("output", "text", "format to output issues as (text, json)")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: lint [-output format] BOARD")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	issues := strategy_board.Validate(loadBoardArg(flags.Arg(0)))
 	switch *output {
+	case "text":
+		for _, issue := range issues {
+			fmt.Println(issue)
+		}
+	case "json":
+		out, err := json.Marshal(issues)
+		if err != nil {
+			panic(err)
+		}
+		os.Stdout.Write(out)
+	default:
+		panic(fmt.Errorf("unknown output format %s", *output))
+	}
+	if strategy_board.HasErrors(issues) {
+		os.Exit(1)
+	}
+}
+
+/* Load a single board from a file, or from the argument itself when it isn't a file. */
+func loadBoardArg(arg string) strategy_board.Board {
+	input := arg
+	if data, err := os.ReadFile(arg); err == nil {
+		input = string(data)
+	}
+	loaded, err := loadBoards(input, "auto")
+	if err != nil {
+		panic(err)
+	}
+	if len(loaded) != 1 {
+		panic(fmt.Errorf("expected one board in %s, found %d", arg, len(loaded)))
+	}
+	return loaded[0]
+}
+
+/*
+Load board from JSON or board description language input, or every board whose share code is found in input.
+With format auto, input is JSON when it starts with a brace, and board description language only when it
+contains no share codes and parses as it, so text without any board is reported as missing input.
+*/
+func loadBoards(input string, format string) ([]strategy_board.Board, error) {
+	if format == "auto" && strings.HasPrefix(strings.TrimSpace(input), "{") {
+		format = "json"
+	}
+	switch format {
+	case "json":
+		board, err := strategy_board.ParseJSON([]byte(input))
+		if err != nil {
+			return nil, err
+		}
+		return []strategy_board.Board{board}, nil
+	case "dsl":
+		board, err := strategy_board.ParseDSL(strings.NewReader(input))
+		if err != nil {
+			return nil, err
+		}
+		return []strategy_board.Board{board}, nil
+	case "auto", "code":
+	default:
+		return nil, fmt.Errorf("unknown input format %s", format)
+	}
+
+	codes, err := strategy_board.FindShareCodes(strings.NewReader(input))
+	if err != nil {
+		return nil, err
+	}
+	if len(codes) == 0 {
+		if format == "auto" && strings.TrimSpace(input) != "" {
+			board, err := strategy_board.ParseDSL(strings.NewReader(input))
+			if err != nil {
+				return nil, fmt.Errorf("%w, no share code found and not board description language (%w)", strategy_board.MissingInput, err)
+			}
+			return []strategy_board.Board{board}, nil
+		}
+		return nil, strategy_board.MissingInput
+	}
+	boards := make([]strategy_board.Board, 0, len(codes))
+	for _, code := range codes {
+		board, err := strategy_board.Load(code.Code)
+		if err != nil {
+			return nil, fmt.Errorf("share code at line %d, column %d: %w", code.Line, code.Column, err)
+		}
+		boards = append(boards, board)
+	}
+	return boards, nil
+}
+
+func fileExtension(output string) string {
+	switch output {
+	case "json":
+		return "json"
+	case "code", "dsl", "hash":
+		return "txt"
+	case "jpeg", "jpg":
+		return "jpg"
+	case "svg":
+		return "svg"
+	}
+	return "png"
+}
+
+func writeBoard(board strategy_board.Board, output string, drawOptions strategy_board.DrawOptions, w io.Writer) error {
+	switch output {
 	case "json":
 		{
 			out, err := json.Marshal(board)
 			if err != nil {
-				panic(err)
+				return err
 			}
-			os.Stdout.Write(out)
-			break
+			_, err = w.Write(out)
+			return err
 		}
-	case "image":
-	case "png":
+	case "dsl":
 		{
-			image, err := strategy_board.Draw(board)
+			_, err := io.WriteString(w, strategy_board.FormatDSL(board))
+			return err
+		}
+	case "hash":
+		{
+			hash, err := board.Hash()
 			if err != nil {
-				panic(err)
+				return err
 			}
-			if err := image.EncodePNG(os.Stdout); err != nil {
-				panic(err)
+			_, err = fmt.Fprintln(w, hash)
+			return err
+		}
+	case "code":
+		{
+			code, err := strategy_board.Encode(board)
+			if err != nil {
+				return err
 			}
-			break
+			_, err = fmt.Fprintln(w, code)
+			return err
 		}
-	case "jpeg":
-	case "jpg":
+	case "image", "png":
 		{
-			image, err := strategy_board.Draw(board)
+			image, err := strategy_board.DrawWithOptions(board, drawOptions)
 			if err != nil {
-				panic(err)
+				return err
 			}
-			if err := jpeg.Encode(os.Stdout, image.Image(), nil); err != nil {
-				panic(err)
+			return image.EncodePNG(w)
+		}
+	case "svg":
+		return strategy_board.DrawSVG(board, w)
+	case "jpeg", "jpg":
+		{
+			image, err := strategy_board.DrawWithOptions(board, drawOptions)
+			if err != nil {
+				return err
 			}
-			break
+			return jpeg.Encode(w, image.Image(), nil)
 		}
 	}
-
+	return fmt.Errorf("unknown output format %s", output)
 }
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"log"
+	"os"
+	"testing"
+
+	strategy_board "github.com/chompy/ffxiv_strat_board_viewer"
+)
+
+func TestMain(m *testing.M) {
+	log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+func TestLoadBoardsFormat(t *testing.T) {
+	dsl := "name \"Raid plan\"\ncircle_aoe at 200,200\n"
+
+	// text without a board is missing input, not a DSL error
+	for _, input := range []string{"", "anyone up for savage tonight?"} {
+		if _, err := loadBoards(input, "auto"); !errors.Is(err, strategy_board.MissingInput) {
+			t.Errorf("%q: got %v, want missing input", input, err)
+		}
+	}
+
+	// DSL is detected, or can be selected or ruled out
+	for _, format := range []string{"auto", "dsl"} {
+		boards, err := loadBoards(dsl, format)
+		if err != nil || len(boards) != 1 || boards[0].Name != "Raid plan" {
+			t.Errorf("%s: got %v, %v, want board named Raid plan", format, boards, err)
+		}
+	}
+	if _, err := loadBoards(dsl, "code"); !errors.Is(err, strategy_board.MissingInput) {
+		t.Errorf("code: got %v, want missing input", err)
+	}
+	if _, err := loadBoards(dsl, "yaml"); err == nil {
+		t.Errorf("yaml: got no error, want unknown format")
+	}
+}
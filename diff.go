@@ -0,0 +1,262 @@
+package strategy_board
+
+import (
+	"fmt"
+	"image/color"
+	"log"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/fogleman/gg"
+)
+
+type ChangeKind string
+
+const (
+	ChangeAdded   ChangeKind = "added"
+	ChangeRemoved ChangeKind = "removed"
+	ChangeChanged ChangeKind = "changed"
+)
+
+/* Field whose value differs between two boards or objects. */
+type FieldChange struct {
+	Field  string `json:"field"`
+	Before any    `json:"before"`
+	After  any    `json:"after"`
+}
+
+/* Object added, removed or changed between two boards, index is -1 on the board the object isn't on. */
+type ObjectChange struct {
+	Kind    ChangeKind    `json:"kind"`
+	TypeID  ObjectType    `json:"type_id"`
+	IndexA  int           `json:"index_a"`
+	IndexB  int           `json:"index_b"`
+	Changes []FieldChange `json:"changes,omitempty"`
+}
+
+/* Differences between two boards. */
+type BoardDiff struct {
+	Board   []FieldChange  `json:"board,omitempty"`
+	Objects []ObjectChange `json:"objects,omitempty"`
+}
+
+func (d BoardDiff) Empty() bool {
+	return len(d.Board) == 0 && len(d.Objects) == 0
+}
+
+/* Human readable list of changes, one per line. */
+func (d BoardDiff) String() string {
+	var out strings.Builder
+	for _, change := range d.Board {
+		fmt.Fprintf(&out, "board %s: %v -> %v\n", change.Field, change.Before, change.After)
+	}
+	for _, change := range d.Objects {
+		switch change.Kind {
+		case ChangeAdded:
+			fmt.Fprintf(&out, "+ object %d (%s)\n", change.IndexB, objectTypeDescription(change.TypeID))
+		case ChangeRemoved:
+			fmt.Fprintf(&out, "- object %d (%s)\n", change.IndexA, objectTypeDescription(change.TypeID))
+		case ChangeChanged:
+			fmt.Fprintf(&out, "~ object %d -> %d (%s)\n", change.IndexA, change.IndexB, objectTypeDescription(change.TypeID))
+			for _, field := range change.Changes {
+				fmt.Fprintf(&out, "    %s: %v -> %v\n", field.Field, field.Before, field.After)
+			}
+		}
+	}
+	return out.String()
+}
+
+func objectTypeDescription(typeId ObjectType) string {
+	if label := objectTypeLabel(typeId); label != "" {
+		return label
+	}
+	return typeId.String()
+}
+
+/*
+Compare two boards. Objects are matched by type, identical objects first and then the closest
+remaining pairs, so a moved or restyled object is reported as changed rather than removed and added.
+*/
+func Diff(a Board, b Board) BoardDiff {
+	out := BoardDiff{Board: make([]FieldChange, 0), Objects: make([]ObjectChange, 0)}
+	if a.Name != b.Name {
+		out.Board = append(out.Board, FieldChange{"name", a.Name, b.Name})
+	}
+	if a.Background != b.Background {
+		out.Board = append(out.Board, FieldChange{"background", a.Background, b.Background})
+	}
+
+	matchA := make([]int, len(a.Objects))
+	matchB := make([]int, len(b.Objects))
+	for i := range matchA {
+		matchA[i] = -1
+	}
+	for i := range matchB {
+		matchB[i] = -1
+	}
+
+	// match identical objects, preferring the same index
+	for i, objectA := range a.Objects {
+		if i < len(b.Objects) && matchB[i] < 0 && len(objectChanges(objectA, b.Objects[i])) == 0 {
+			matchA[i], matchB[i] = i, i
+		}
+	}
+	for i, objectA := range a.Objects {
+		for j, objectB := range b.Objects {
+			if matchA[i] < 0 && matchB[j] < 0 && len(objectChanges(objectA, objectB)) == 0 {
+				matchA[i], matchB[j] = j, i
+			}
+		}
+	}
+
+	// match remaining objects of the same type, closest pairs first
+	type pair struct {
+		i, j int
+		cost float64
+	}
+	pairs := make([]pair, 0)
+	for i, objectA := range a.Objects {
+		for j, objectB := range b.Objects {
+			if matchA[i] < 0 && matchB[j] < 0 && objectA.TypeID == objectB.TypeID {
+				pairs = append(pairs, pair{i, j, matchCost(objectA, objectB)})
+			}
+		}
+	}
+	sort.SliceStable(pairs, func(x, y int) bool { return pairs[x].cost < pairs[y].cost })
+	for _, p := range pairs {
+		if matchA[p.i] < 0 && matchB[p.j] < 0 {
+			matchA[p.i], matchB[p.j] = p.j, p.i
+		}
+	}
+
+	// report changes in the order of the second board, followed by removed objects
+	for j, objectB := range b.Objects {
+		i := matchB[j]
+		if i < 0 {
+			out.Objects = append(out.Objects, ObjectChange{Kind: ChangeAdded, TypeID: objectB.TypeID, IndexA: -1, IndexB: j})
+			continue
+		}
+		if changes := objectChanges(a.Objects[i], objectB); len(changes) > 0 {
+			out.Objects = append(out.Objects, ObjectChange{Kind: ChangeChanged, TypeID: objectB.TypeID, IndexA: i, IndexB: j, Changes: changes})
+		}
+	}
+	for i, objectA := range a.Objects {
+		if matchA[i] < 0 {
+			out.Objects = append(out.Objects, ObjectChange{Kind: ChangeRemoved, TypeID: objectA.TypeID, IndexA: i, IndexB: -1})
+		}
+	}
+	return out
+}
+
+/* Cost of treating two objects of the same type as one, distance in pixels plus a penalty per changed field. */
+func matchCost(a Object, b Object) float64 {
+	ax, ay := a.PixelPosition()
+	bx, by := b.PixelPosition()
+	cost := math.Hypot(ax-bx, ay-by)
+	for _, change := range objectChanges(a, b) {
+		if change.Field != "position" {
+			cost += 100
+		}
+	}
+	return cost
+}
+
+/* Fields that differ between two objects, layer order isn't compared. */
+func objectChanges(a Object, b Object) []FieldChange {
+	out := make([]FieldChange, 0)
+	if a.TypeID != b.TypeID {
+		out = append(out, FieldChange{"type_id", a.TypeID, b.TypeID})
+	}
+	if a.X != b.X || a.Y != b.Y {
+		out = append(out, FieldChange{"position", formatDSLPoint(a.PixelPosition()), formatDSLPoint(b.PixelPosition())})
+	}
+	if a.Angle != b.Angle {
+		out = append(out, FieldChange{"angle", a.Angle, b.Angle})
+	}
+	if a.Scale != b.Scale {
+		out = append(out, FieldChange{"scale", a.Scale, b.Scale})
+	}
+	if a.Color != b.Color {
+		out = append(out, FieldChange{"color", FormatHexColor(a.Color), FormatHexColor(b.Color)})
+	}
+	if a.Text != b.Text {
+		out = append(out, FieldChange{"text", a.Text, b.Text})
+	}
+	if a.Visible != b.Visible {
+		out = append(out, FieldChange{"visible", a.Visible, b.Visible})
+	}
+	if a.FlipHorizontal != b.FlipHorizontal {
+		out = append(out, FieldChange{"flip_horizontal", a.FlipHorizontal, b.FlipHorizontal})
+	}
+	if a.FlipVertical != b.FlipVertical {
+		out = append(out, FieldChange{"flip_vertical", a.FlipVertical, b.FlipVertical})
+	}
+	if a.Locked != b.Locked {
+		out = append(out, FieldChange{"locked", a.Locked, b.Locked})
+	}
+	if a.RawFlags()&^knownObjectFlags != b.RawFlags()&^knownObjectFlags {
+		out = append(out, FieldChange{"flags", a.RawFlags(), b.RawFlags()})
+	}
+	paramsA, paramsB := paddedParams(a.Params), paddedParams(b.Params)
+	if !slices.Equal(paramsA, paramsB) {
+		out = append(out, FieldChange{"params", paramsA, paramsB})
+	}
+	return out
+}
+
+func paddedParams(params []int) []int {
+	out := make([]int, max(3, len(params)))
+	copy(out, params)
+	return out
+}
+
+/* Colors used to highlight changes when drawing a diff. */
+var diffColors = map[ChangeKind]color.NRGBA{
+	ChangeAdded:   {60, 200, 80, 255},
+	ChangeRemoved: {230, 60, 60, 255},
+	ChangeChanged: {240, 200, 40, 255},
+}
+
+const diffHighlightRadius = 40
+
+/* Draw both boards side by side, circling added objects in green, removed in red and changed in yellow. */
+func DrawDiff(a Board, b Board) (*gg.Context, error) {
+	diff := Diff(a, b)
+	ca, err := Draw(a)
+	if err != nil {
+		return nil, err
+	}
+	cb, err := Draw(b)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Draw strategy board diff with %d changed objects", len(diff.Objects))
+	c := gg.NewContext(canvasWidth*2, canvasHeight)
+	c.DrawImage(ca.Image(), 0, 0)
+	c.DrawImage(cb.Image(), canvasWidth, 0)
+
+	c.SetLineWidth(4)
+	for _, change := range diff.Objects {
+		c.SetColor(diffColors[change.Kind])
+		if change.IndexA >= 0 {
+			x, y := a.Objects[change.IndexA].PixelPosition()
+			c.DrawCircle(x, y, diffHighlightRadius)
+			c.Stroke()
+		}
+		if change.IndexB >= 0 {
+			x, y := b.Objects[change.IndexB].PixelPosition()
+			c.DrawCircle(x+canvasWidth, y, diffHighlightRadius)
+			c.Stroke()
+		}
+	}
+
+	// divider between the two boards
+	c.SetColor(color.White)
+	c.DrawLine(canvasWidth, 0, canvasWidth, canvasHeight)
+	c.Stroke()
+
+	return c, nil
+}
@@ -9,26 +9,91 @@ import (
 	"slices"
 
 	"github.com/fogleman/gg"
+	xdraw "golang.org/x/image/draw"
 )
 
 const canvasWidth = 1024
 const canvasHeight = 768
 
+/* Canvas objects are drawn on, positions on the default 1024x768 canvas are mapped to it by scale and offset. */
+type canvas struct {
+	*gg.Context
+	scale   float64
+	offsetX float64
+	offsetY float64
+	options DrawOptions
+	scratch *canvas
+}
+
+func newCanvas(width int, height int, scale float64, offsetX float64, offsetY float64, options DrawOptions) *canvas {
+	c := &canvas{gg.NewContext(width, height), scale, offsetX, offsetY, options, nil}
+	c.reset()
+	return c
+}
+
+/* Reset transform to map positions on the default canvas onto this canvas. */
+func (c *canvas) reset() {
+	c.Identity()
+	c.Translate(c.offsetX, c.offsetY)
+	c.Scale(c.scale, c.scale)
+}
+
+/*
+Empty canvas with the same size and transform. The same canvas is cleared and returned on every call so
+a draw allocates one per canvas however many objects need one, whatever was drawn on it has to be copied
+off before the next call.
+*/
+func (c *canvas) blank() *canvas {
+	if c.scratch == nil {
+		c.scratch = newCanvas(c.Width(), c.Height(), c.scale, c.offsetX, c.offsetY, c.options)
+		return c.scratch
+	}
+	s := c.scratch
+	clear(s.Image().(*image.RGBA).Pix)
+	s.ClearPath()
+	s.ResetClip()
+	s.SetDash()
+	s.reset()
+	return s
+}
+
 func Draw(board Board) (*gg.Context, error) {
+	return DrawWithOptions(board, DefaultDrawOptions)
+}
+
+/* Draw board with options for output size, hidden objects and background. */
+func DrawWithOptions(board Board, options DrawOptions) (*gg.Context, error) {
+	width, height, scale, offsetX, offsetY, err := options.layout()
+	if err != nil {
+		return nil, err
+	}
+	supersample := max(options.Supersample, 1)
+
 	// load assets for given board
 	assetList, err := board.Assets()
 	if err != nil {
 		return nil, err
 	}
 
-	log.Println("Draw strategy board")
+	log.Printf("Draw strategy board (%dx%d)", width, height)
 
 	// create canvas
-	c := gg.NewContext(canvasWidth, canvasHeight)
+	ss := float64(supersample)
+	c := newCanvas(width*supersample, height*supersample, scale*ss, offsetX*ss, offsetY*ss, options)
+	if options.BackgroundColor.A > 0 {
+		c.SetColor(options.BackgroundColor)
+		c.Clear()
+	}
+
+	// keep objects off the margins when the board doesn't fill the canvas
+	if offsetX > 0 || offsetY > 0 {
+		c.DrawRectangle(0, 0, canvasWidth, canvasHeight)
+		c.Clip()
+	}
 
 	// draw background
 	for _, asset := range assetList {
-		if asset.ID == -1 {
+		if asset.ID == -1 && !options.HideBackground {
 			log.Printf("  - Draw background (ID=%d)", board.Background)
 			c.DrawImage(asset.Image, 0, 0)
 			break
@@ -42,29 +107,39 @@ func Draw(board Board) (*gg.Context, error) {
 		}
 	}
 
-	return c, nil
+	if supersample == 1 {
+		return c.Context, nil
+	}
+	log.Printf("  - Downsample from %dx%d", c.Width(), c.Height())
+	out := image.NewRGBA(image.Rect(0, 0, width, height))
+	xdraw.CatmullRom.Scale(out, out.Bounds(), c.Image(), c.Image().Bounds(), xdraw.Src, nil)
+	return gg.NewContextForRGBA(out), nil
 }
 
-func drawObject(object Object, assets []Asset, c *gg.Context) error {
+func drawObject(object Object, assets []Asset, c *canvas) error {
 	log.Printf("  - Draw object (TYPE=%d)", object.TypeID)
 	if !object.Visible {
+		if c.options.ShowHidden {
+			log.Println("    - Object not visible, drawing faded")
+			return drawHiddenObject(object, assets, c)
+		}
 		log.Println("    - Object not visible, skipping")
 		return nil
 	}
 	switch object.TypeID {
-	case 10:
+	case ObjectTypeCircleAoE:
 		arcImage, err := loadArcImage(nil)
 		if err != nil {
 			return err
 		}
 		return drawArc(object, arcImage, c)
-	case 11:
+	case ObjectTypeLineAoE:
 		return drawLineAoe(object, c)
-	case 12:
+	case ObjectTypeLine:
 		return drawLine(object, c)
-	case 17:
+	case ObjectTypeDonut:
 		return drawArc(object, nil, c)
-	case 100:
+	case ObjectTypeText:
 		return drawTextObject(object, c)
 	default:
 		{
@@ -79,74 +154,158 @@ func drawObject(object Object, assets []Asset, c *gg.Context) error {
 	return AssetNotFound
 }
 
-func drawTextObject(object Object, c *gg.Context) error {
-	if object.TypeID != 100 {
+/* Alpha of hidden objects and the dash pattern outlining them, when hidden objects are shown. */
+const hiddenObjectAlpha = 90
+
+var hiddenObjectDash = []float64{6, 4}
+
+/* Draw hidden object faded on its own canvas and outline its bounds with a dashed line. */
+func drawHiddenObject(object Object, assets []Asset, c *canvas) error {
+	object.Visible = true
+	nc := c.blank()
+	if err := drawObject(object, assets, nc); err != nil {
+		return err
+	}
+	// fade in place, pixels are premultiplied so every channel is scaled
+	faded := nc.Image().(*image.RGBA)
+	for i, v := range faded.Pix {
+		faded.Pix[i] = uint8(uint32(v) * hiddenObjectAlpha / 255)
+	}
+	c.Identity()
+	c.DrawImage(faded, 0, 0)
+	c.reset()
+
+	bounds, err := object.Bounds(assets)
+	if err != nil {
+		return err
+	}
+	if bounds.Empty() {
+		return nil
+	}
+	dashes := make([]float64, len(hiddenObjectDash))
+	for i, dash := range hiddenObjectDash {
+		dashes[i] = dash * c.scale
+	}
+	c.SetDash(dashes...)
+	c.SetLineWidth(2 * c.scale)
+	c.SetColor(color.NRGBA{255, 255, 255, 160})
+	c.DrawRectangle(bounds.MinX, bounds.MinY, bounds.Width(), bounds.Height())
+	c.Stroke()
+	c.SetDash()
+	return nil
+}
+
+func drawTextObject(object Object, c *canvas) error {
+	if object.TypeID != ObjectTypeText {
 		return DrawUnexpectedObjectError
 	}
 	if object.Text == "" {
 		return nil
 	}
-	fontFace, err := loadFont(nil)
+	// text is drawn untransformed with a font sized for the canvas so it stays sharp
+	fontFace, err := loadFontFace(nil, assetFontSize*c.scale)
 	if err != nil {
 		return err
 	}
+	x, y := c.TransformPoint(object.PixelPosition())
+	shadow := 2 * c.scale
+	c.Identity()
 	c.SetFontFace(fontFace)
 	c.SetColor(color.NRGBA{0, 0, 0, object.Color.A})
-	c.DrawStringAnchored(object.Text, float64(object.X), float64(object.Y), 0.5, 0.5)
+	c.DrawStringAnchored(object.Text, x, y, 0.5, 0.5)
 	c.SetColor(object.Color)
-	c.DrawStringAnchored(object.Text, float64(object.X)-2, float64(object.Y)-2, 0.5, 0.5)
-	c.Identity()
+	c.DrawStringAnchored(object.Text, x-shadow, y-shadow, 0.5, 0.5)
+	c.reset()
 	return nil
 }
 
-func drawImageObject(object Object, asset *Asset, c *gg.Context) error {
-	c.Translate(float64(object.X), float64(object.Y))
+func drawImageObject(object Object, asset *Asset, c *canvas) error {
+	c.Translate(object.PixelPosition())
 	c.Scale(object.ScaleFactor(asset.Scale))
 	c.Rotate(gg.Radians(float64(object.Angle)))
-
-	// TODO image transparency
-
-	c.DrawImageAnchored(asset.Image, 0, 0, .5, .5)
-	c.Identity()
+	c.DrawImageAnchored(fadeImage(asset.Image, object.Color.A), 0, 0, .5, .5)
+	c.reset()
 	return nil
 }
 
-func drawLineAoe(object Object, c *gg.Context) error {
-	c.Translate(float64(object.X), float64(object.Y))
+/* Fade an asset image by an object's color alpha, sprites keep their own colors. */
+func fadeImage(src image.Image, alpha uint8) image.Image {
+	if alpha == 255 {
+		return src
+	}
+	a := uint32(alpha)
+	bounds := src.Bounds()
+	out := image.NewRGBA64(bounds)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			// colors are alpha premultiplied, so fading scales every channel
+			sr, sg, sb, sa := src.At(x, y).RGBA()
+			out.SetRGBA64(x, y, color.RGBA64{uint16(sr * a / 255), uint16(sg * a / 255), uint16(sb * a / 255), uint16(sa * a / 255)})
+		}
+	}
+	return out
+}
+
+func drawLineAoe(object Object, c *canvas) error {
+	params, err := object.LineAoE()
+	if err != nil {
+		return err
+	}
+	c.Translate(object.PixelPosition())
 	c.Rotate(gg.Radians(float64(object.Angle)))
-	w, h := float64(object.Params[0]), float64(object.Params[1])
+	w, h := float64(params.HalfWidth), float64(params.HalfHeight)
 	c.DrawRectangle(-w, -h, w*2, h*2)
 	c.SetColor(object.Color)
 	c.Fill()
-	c.Identity()
+	c.reset()
 	return nil
 }
 
-func drawLine(object Object, c *gg.Context) error {
-	x2, y2 := math.Round(float64(object.Params[0])/5120*canvasWidth), math.Round(float64(object.Params[1])/3840*canvasHeight)
-	c.SetLineWidth(float64(object.Params[2]) * 2)
+func drawLine(object Object, c *canvas) error {
+	params, err := object.Line()
+	if err != nil {
+		return err
+	}
+	// line width and point size aren't transformed, scale them to the canvas
+	thickness := float64(params.Thickness) * c.scale
+	c.SetLineWidth(thickness * 2)
 	c.SetColor(object.Color)
-	c.MoveTo(float64(object.X), float64(object.Y))
-	c.LineTo(x2, y2)
+	c.MoveTo(params.X1, params.Y1)
+	c.LineTo(params.X2, params.Y2)
 	c.Stroke()
 	c.SetColor(color.NRGBA{255, 255, 255, object.Color.A})
-	c.DrawPoint(float64(object.X), float64(object.Y), float64(object.Params[2]))
+	c.DrawPoint(params.X1, params.Y1, thickness)
 	c.Fill()
 	c.SetColor(color.NRGBA{255, 255, 255, object.Color.A})
-	c.DrawPoint(x2, y2, float64(object.Params[2]))
+	c.DrawPoint(params.X2, params.Y2, thickness)
 	c.Fill()
-	c.Identity()
+	c.reset()
 	return nil
 }
 
-func drawArc(object Object, image image.Image, c *gg.Context) error {
+/* Shape of an arc object, its angles in radians and radii before the object's own scale is applied. */
+type arcShape struct {
+	startAngle  float64
+	endAngle    float64
+	innerRadius float64
+	outerRadius float64
+	// offset from the object's position to the center of the arc's bounding box
+	ox, oy float64
+}
+
+func (o Object) arcShape() (arcShape, error) {
+	params, err := o.Arc()
+	if err != nil {
+		return arcShape{}, err
+	}
+
 	// calculate the angle of the arc and its radius
-	arcAngle := float64(object.Params[0]) / 180.0 * math.Pi
+	arcAngle := float64(params.Angle) / 180.0 * math.Pi
 	startAngle := -math.Pi / 2.0
 	endAngle := startAngle + arcAngle
-	innerRadius := float64(object.Params[1])
+	innerRadius := float64(params.InnerRadius)
 	outerRadius := 256.0
-	if object.TypeID == 17 {
+	if o.TypeID == ObjectTypeDonut {
 		outerRadius = 250.0
 	}
 
@@ -168,11 +327,32 @@ func drawArc(object Object, image image.Image, c *gg.Context) error {
 		rightEdge = (1 - math.Sin(arcAngle)) * outerRadius
 	}
 
-	ox, oy := -(leftEdge-rightEdge)/2.0, bottomEdge/2.0
+	return arcShape{startAngle, endAngle, innerRadius, outerRadius, -(leftEdge - rightEdge) / 2.0, bottomEdge / 2.0}, nil
+}
+
+/* Transform from arc coordinates to canvas coordinates, the same one drawArc applies. */
+func (o Object) arcMatrix(shape arcShape) gg.Matrix {
+	x, y := o.PixelPosition()
+	sx, sy := o.ScaleFactor(.02)
+	return gg.Identity().
+		Translate(x+shape.ox, y+shape.oy).
+		Translate(-shape.ox, -shape.oy).Rotate(gg.Radians(float64(o.Angle))).Translate(shape.ox, shape.oy).
+		Translate(-shape.ox, -shape.oy).Scale(sx, sy).Translate(shape.ox, shape.oy)
+}
+
+func drawArc(object Object, image image.Image, c *canvas) error {
+	shape, err := object.arcShape()
+	if err != nil {
+		return err
+	}
+	startAngle, endAngle := shape.startAngle, shape.endAngle
+	innerRadius, outerRadius := shape.innerRadius, shape.outerRadius
+	ox, oy := shape.ox, shape.oy
 
 	// draw the arc and its inner circle
-	nc := gg.NewContext(canvasWidth, canvasHeight)
-	nc.Translate(float64(object.X)+ox, float64(object.Y)+oy)
+	nc := c.blank()
+	x, y := object.PixelPosition()
+	nc.Translate(x+ox, y+oy)
 	nc.RotateAbout(gg.Radians(float64(object.Angle)), -ox, -oy)
 	sx, sy := object.ScaleFactor(.02)
 	nc.ScaleAbout(sx, sy, -ox, -oy)
@@ -181,19 +361,21 @@ func drawArc(object Object, image image.Image, c *gg.Context) error {
 	nc.DrawArc(0, 0, innerRadius, endAngle, startAngle)
 
 	if image != nil {
-		// draw arc using image as mask
+		// draw arc using image as mask, the image is stretched over the full circle
+		// around the arc's center so it follows the object's scale, rotation and flips
 		nc.Clip()
-		// TODO fix
-		//csx, csy := object.ScaleFactor(.01)
-		//nc.ScaleAbout(csx, csy, -ox, -oy)
-		nc.DrawImageAnchored(image, int(ox), int(oy), 0.5, 0.5)
+		size := image.Bounds().Size()
+		nc.Scale(outerRadius*2/float64(size.X), outerRadius*2/float64(size.Y))
+		nc.DrawImageAnchored(fadeImage(image, object.Color.A), 0, 0, 0.5, 0.5)
 	} else {
 		// draw arc using solid color
 		nc.SetColor(color.NRGBA{254, 161, 49, object.Color.A})
 		nc.Fill()
 	}
 
+	c.Identity()
 	c.DrawImage(nc.Image(), 0, 0)
+	c.reset()
 
 	return nil
 
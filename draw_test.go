@@ -0,0 +1,257 @@
+package strategy_board
+
+import (
+	"flag"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/fogleman/gg"
+)
+
+var updateRenders = flag.Bool("update", false, "write reference renders in testdata/render instead of comparing against them")
+
+/* Largest difference in any channel allowed between a render and its reference, for rounding differences. */
+const renderTolerance = 2
+
+/* Compare image against the reference render testdata/render/NAME.png, or replace it when run with -update. */
+func checkReferenceRender(t *testing.T, name string, got image.Image) {
+	t.Helper()
+	path := filepath.Join("testdata", "render", name+".png")
+	if *updateRenders {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		writePNG(t, path, got)
+		return
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("%s: %v, run with -update to create it", name, err)
+	}
+	defer f.Close()
+	want, err := png.Decode(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Bounds() != want.Bounds() {
+		t.Fatalf("%s: got size %v, want %v", name, got.Bounds(), want.Bounds())
+	}
+	for y := want.Bounds().Min.Y; y < want.Bounds().Max.Y; y++ {
+		for x := want.Bounds().Min.X; x < want.Bounds().Max.X; x++ {
+			g := color.NRGBAModel.Convert(got.At(x, y)).(color.NRGBA)
+			w := color.NRGBAModel.Convert(want.At(x, y)).(color.NRGBA)
+			if channelDiff(g, w) > renderTolerance {
+				out := filepath.Join(t.TempDir(), name+".png")
+				writePNG(t, out, got)
+				t.Fatalf("%s: pixel %d,%d is %v, want %v, render written to %s", name, x, y, g, w, out)
+			}
+		}
+	}
+}
+
+func channelDiff(a color.NRGBA, b color.NRGBA) int {
+	diff := 0
+	for _, pair := range [][2]uint8{{a.R, b.R}, {a.G, b.G}, {a.B, b.B}, {a.A, b.A}} {
+		diff = max(diff, int(pair[0])-int(pair[1]), int(pair[1])-int(pair[0]))
+	}
+	return diff
+}
+
+func writePNG(t *testing.T, path string, img image.Image) {
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := png.Encode(f, img); err != nil {
+		t.Fatal(err)
+	}
+}
+
+/* Canvas at half the default size filled gray, so faded and transparent pixels show in reference renders. */
+func testCanvas() *canvas {
+	c := newCanvas(canvasWidth/2, canvasHeight/2, 0.5, 0, 0, DefaultDrawOptions)
+	c.SetColor(color.NRGBA{96, 96, 96, 255})
+	c.Clear()
+	return c
+}
+
+/* Sprite with its own colors and transparent corners, so the tests don't depend on the packed assets. */
+func testSprite() Asset {
+	dc := gg.NewContext(64, 64)
+	dc.DrawCircle(32, 32, 30)
+	dc.SetColor(color.NRGBA{220, 40, 40, 255})
+	dc.Fill()
+	dc.DrawRectangle(16, 24, 32, 16)
+	dc.SetColor(color.NRGBA{40, 80, 220, 255})
+	dc.Fill()
+	return Asset{ID: 9000, Name: "Test Marker", Scale: 0.02, Image: dc.Image()}
+}
+
+func TestFadeImage(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 1, 1))
+	src.SetNRGBA(0, 0, color.NRGBA{200, 100, 50, 255})
+	if got := fadeImage(src, 255); got != image.Image(src) {
+		t.Errorf("opaque fade returned a copy, want the image itself")
+	}
+	got := color.NRGBAModel.Convert(fadeImage(src, 128).At(0, 0)).(color.NRGBA)
+	if want := (color.NRGBA{200, 100, 50, 128}); channelDiff(got, want) > 1 {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	got = color.NRGBAModel.Convert(fadeImage(src, 0).At(0, 0)).(color.NRGBA)
+	if got.A != 0 {
+		t.Errorf("got %v, want transparent", got)
+	}
+}
+
+func TestDrawImageObjectAlpha(t *testing.T) {
+	asset := testSprite()
+	c := testCanvas()
+	// a row of sprites from opaque to fully transparent, then faded ones overlapping
+	for i, alpha := range []uint8{255, 191, 128, 64, 0} {
+		object := Object{TypeID: asset.ID, Visible: true, Scale: 100, Color: color.NRGBA{255, 255, 255, alpha}}
+		object.SetPixelPosition(float64(152+i*180), 200)
+		if err := drawImageObject(object, &asset, c); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i, alpha := range []uint8{128, 128} {
+		object := Object{TypeID: asset.ID, Visible: true, Scale: 150, Angle: 30, Color: color.NRGBA{255, 0, 0, alpha}}
+		object.SetPixelPosition(float64(420+i*80), 520)
+		if err := drawImageObject(object, &asset, c); err != nil {
+			t.Fatal(err)
+		}
+	}
+	checkReferenceRender(t, "sprite_alpha", c.Image())
+}
+
+/* Texture with a differently colored quadrant each way, so a wrong crop, turn or flip shows. */
+var testQuadrantColors = [2][2]color.NRGBA{
+	{{220, 40, 40, 255}, {40, 180, 60, 255}},
+	{{40, 80, 220, 255}, {230, 200, 40, 255}},
+}
+
+func testQuadrantTexture() image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, 64, 64))
+	for y := range 64 {
+		for x := range 64 {
+			img.SetNRGBA(x, y, testQuadrantColors[y/32][x/32])
+		}
+	}
+	return img
+}
+
+/*
+Canvas at a quarter of the default scale, centered on the middle of the default canvas and large enough
+for a full circle AoE at the largest scale, filled gray.
+*/
+func testArcCanvas() *canvas {
+	const size, scale = 640, 0.25
+	c := newCanvas(size, size, scale, size/2-canvasWidth/2*scale, size/2-canvasHeight/2*scale, DefaultDrawOptions)
+	c.SetColor(color.NRGBA{96, 96, 96, 255})
+	c.Clear()
+	return c
+}
+
+func TestDrawArcReferenceRenders(t *testing.T) {
+	texture := testQuadrantTexture()
+	arc := func(scale int, angle int, fan int) Object {
+		object := Object{TypeID: ObjectTypeCircleAoE, Visible: true, Scale: scale, Angle: angle, Color: color.NRGBA{255, 255, 255, 255}, Params: []int{fan, 0, 0}}
+		object.SetPixelPosition(canvasWidth/2, canvasHeight/2)
+		return object
+	}
+	flipped := arc(100, 30, 90)
+	flipped.FlipHorizontal = true
+	faded := arc(100, 0, 270)
+	faded.Color.A = 128
+	donut := arc(100, -45, 270)
+	donut.TypeID = ObjectTypeDonut
+	donut.Params[1] = 100
+
+	tests := []struct {
+		name    string
+		object  Object
+		texture image.Image
+	}{
+		{"arc_scale_50", arc(50, 0, 360), texture},
+		{"arc_scale_100", arc(100, 0, 360), texture},
+		{"arc_scale_200", arc(200, 0, 360), texture},
+		{"arc_fan_90", arc(100, 0, 90), texture},
+		{"arc_fan_270", arc(100, 0, 270), texture},
+		{"arc_fan_360_rotated", arc(100, 45, 360), texture},
+		{"arc_fan_90_flipped", flipped, texture},
+		{"arc_fan_270_faded", faded, texture},
+		{"donut_fan_270", donut, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := testArcCanvas()
+			if err := drawArc(test.object, test.texture, c); err != nil {
+				t.Fatal(err)
+			}
+			checkReferenceRender(t, test.name, c.Image())
+		})
+	}
+}
+
+func TestDrawArcTextureFollowsScale(t *testing.T) {
+	texture := testQuadrantTexture()
+	for _, scale := range []int{50, 100, 200} {
+		object := Object{TypeID: ObjectTypeCircleAoE, Visible: true, Scale: scale, Color: color.NRGBA{255, 255, 255, 255}, Params: []int{360, 0, 0}}
+		object.SetPixelPosition(canvasWidth/2, canvasHeight/2)
+		c := testArcCanvas()
+		if err := drawArc(object, texture, c); err != nil {
+			t.Fatal(err)
+		}
+		// the middle of each quarter of the circle shows the matching quadrant of the texture at any scale
+		shape, err := object.arcShape()
+		if err != nil {
+			t.Fatal(err)
+		}
+		m := object.arcMatrix(shape).Multiply(gg.Identity().Translate(c.offsetX, c.offsetY).Scale(c.scale, c.scale))
+		r := shape.outerRadius / 2
+		for qy, ay := range []float64{-r, r} {
+			for qx, ax := range []float64{-r, r} {
+				x, y := m.TransformPoint(ax, ay)
+				got := color.NRGBAModel.Convert(c.Image().At(int(x), int(y))).(color.NRGBA)
+				if want := testQuadrantColors[qy][qx]; channelDiff(got, want) > renderTolerance {
+					t.Errorf("scale %d: quadrant %d,%d at %d,%d is %v, want %v", scale, qx, qy, int(x), int(y), got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestDrawConcurrently(t *testing.T) {
+	// draws only interleave, and so only race, with more than one thread running them
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+	board := testBoards()[1]
+	options := DefaultDrawOptions
+	options.ShowHidden = true
+	var wg sync.WaitGroup
+	errs := make(chan error, 6)
+	for i := range 6 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// differently sized text needs faces of different sizes
+			options := options
+			options.Scale = 0.5 + float64(i%3)/4
+			_, err := DrawWithOptions(board, options)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+}
@@ -0,0 +1,357 @@
+package strategy_board
+
+import (
+	"bufio"
+	"fmt"
+	"image/color"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+/*
+Strategy board description language, one statement per line:
+
+	name "Raid plan"
+	background 3
+	tank at 512,300
+	circle_aoe at 200,200 scale 150 color #ff000080 arc 90 inner_radius 20
+	line at 100,100 to 300,300 thickness 8
+	text "Stack" at 500,100
+
+Objects are listed front to back, the same order as Board.Objects. Positions are pixels on
+the default 1024x768 canvas. Object types are named in snake case, or as "object <type id>".
+Lines starting with # or // are comments.
+
+Object attributes:
+
+	at X,Y                        position
+	angle N                       rotation in degrees
+	scale N                       scale in percent
+	color #RRGGBBAA               color, alpha is optional
+	hidden                        object is not visible
+	flip_horizontal, flip_vertical
+	locked
+	flags N                       raw flag word, for flags without a keyword
+	arc N, inner_radius N         circle AoE and donut params
+	half_width N, half_height N   line AoE params
+	to X,Y, thickness N           line params
+	param1 N, param2 N, param3 N  params of other object types
+*/
+
+/* Error in board description language with the line it occurred on. */
+type DSLError struct {
+	Line int
+	Err  error
+}
+
+func (e *DSLError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Err)
+}
+
+func (e *DSLError) Unwrap() error {
+	return e.Err
+}
+
+/* Parse board description language into a strategy board. */
+func ParseDSL(r io.Reader) (Board, error) {
+	board := Board{Background: DefaultBackground, Objects: make([]Object, 0)}
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//") {
+			continue
+		}
+		tokens, err := tokenizeDSL(line)
+		if err != nil {
+			return Board{}, &DSLError{lineNumber, err}
+		}
+		if err := parseDSLStatement(tokens, &board); err != nil {
+			return Board{}, &DSLError{lineNumber, err}
+		}
+		if err := checkBoardLimits(board); err != nil {
+			return Board{}, &DSLError{lineNumber, err}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return Board{}, err
+	}
+	return board, nil
+}
+
+/* Split line into whitespace separated tokens, quoted strings are unquoted into a single token. */
+func tokenizeDSL(line string) ([]string, error) {
+	tokens := make([]string, 0)
+	for line = strings.TrimSpace(line); line != ""; line = strings.TrimSpace(line) {
+		if line[0] == '"' {
+			prefix, err := strconv.QuotedPrefix(line)
+			if err != nil {
+				return nil, fmt.Errorf("%w: unterminated string", DSLSyntaxError)
+			}
+			value, _ := strconv.Unquote(prefix)
+			tokens = append(tokens, value)
+			line = line[len(prefix):]
+			continue
+		}
+		end := strings.IndexFunc(line, unicode.IsSpace)
+		if end < 0 {
+			end = len(line)
+		}
+		tokens = append(tokens, line[:end])
+		line = line[end:]
+	}
+	return tokens, nil
+}
+
+func parseDSLStatement(tokens []string, board *Board) error {
+	switch tokens[0] {
+	case "name":
+		if len(tokens) != 2 {
+			return fmt.Errorf("%w: expected name \"<name>\"", DSLSyntaxError)
+		}
+		board.Name = tokens[1]
+		return nil
+	case "background":
+		if len(tokens) != 2 {
+			return fmt.Errorf("%w: expected background <id>", DSLSyntaxError)
+		}
+		background, err := parseDSLInt(tokens[1])
+		board.Background = background
+		return err
+	}
+	object, err := parseDSLObject(tokens)
+	if err != nil {
+		return err
+	}
+	board.Objects = append(board.Objects, object)
+	return nil
+}
+
+func parseDSLObject(tokens []string) (Object, error) {
+	// object type, by name or id
+	var typeId ObjectType
+	if tokens[0] == "object" {
+		if len(tokens) < 2 {
+			return Object{}, fmt.Errorf("%w: expected object <type id>", DSLSyntaxError)
+		}
+		id, err := parseDSLInt(tokens[1])
+		if err != nil {
+			return Object{}, err
+		}
+		typeId = ObjectType(id)
+		tokens = tokens[2:]
+	} else {
+		id, found, err := objectTypeByLabel(tokens[0])
+		if err != nil {
+			return Object{}, err
+		}
+		if !found {
+			return Object{}, fmt.Errorf("%w: %q", UnknownObjectTypeError, tokens[0])
+		}
+		typeId = id
+		tokens = tokens[1:]
+	}
+
+	object := Object{
+		TypeID:  typeId,
+		Visible: true,
+		Color:   color.NRGBA{255, 255, 255, 255},
+		Scale:   DefaultObjectScale,
+		Params:  defaultParams(typeId),
+	}
+
+	// text objects are followed by their text
+	if typeId == ObjectTypeText {
+		if len(tokens) == 0 {
+			return Object{}, fmt.Errorf("%w: expected text after object type", DSLSyntaxError)
+		}
+		object.Text = tokens[0]
+		tokens = tokens[1:]
+	}
+
+	for len(tokens) > 0 {
+		keyword := tokens[0]
+		tokens = tokens[1:]
+		switch keyword {
+		case "hidden":
+			object.Visible = false
+			continue
+		case "flip_horizontal":
+			object.FlipHorizontal = true
+			continue
+		case "flip_vertical":
+			object.FlipVertical = true
+			continue
+		case "locked":
+			object.Locked = true
+			continue
+		}
+
+		if len(tokens) == 0 {
+			return Object{}, fmt.Errorf("%w: expected value after %s", DSLSyntaxError, keyword)
+		}
+		value := tokens[0]
+		tokens = tokens[1:]
+		var err error
+		switch keyword {
+		case "at":
+			var x, y float64
+			if x, y, err = parseDSLPoint(value); err == nil {
+				object.SetPixelPosition(x, y)
+			}
+		case "to":
+			var x, y float64
+			if x, y, err = parseDSLPoint(value); err == nil && typeId == ObjectTypeLine {
+				object.Params[0], object.Params[1] = CanvasToNative(x, y, canvasWidth, canvasHeight)
+			} else if err == nil {
+				err = fmt.Errorf("%w: %s is only used by lines", UnknownParamError, keyword)
+			}
+		case "angle":
+			object.Angle, err = parseDSLInt(value)
+		case "scale":
+			object.Scale, err = parseDSLInt(value)
+		case "color":
+			object.Color, err = ParseHexColor(value)
+		case "flags":
+			var flags int
+			flags, err = parseDSLInt(value)
+			object.Flags = BoardObjectFlag(flags)
+		default:
+			index := dslParamIndex(typeId, keyword)
+			if index < 0 {
+				return Object{}, fmt.Errorf("%w: unexpected %q", DSLSyntaxError, keyword)
+			}
+			object.Params[index], err = parseDSLInt(value)
+		}
+		if err != nil {
+			return Object{}, err
+		}
+	}
+
+	// limits are checked by ParseDSL once the object is on the board, so errors have its index
+	return object, nil
+}
+
+/* DSL keyword of a param, arc angle is named arc so it doesn't clash with the object's angle. */
+func dslParamKeyword(typeId ObjectType, index int) string {
+	if typeId == ObjectTypeLine && index < 2 {
+		return "to"
+	}
+	label := paramLabel(typeId, index)
+	if label == "angle" {
+		return "arc"
+	}
+	return label
+}
+
+func dslParamIndex(typeId ObjectType, keyword string) int {
+	for i := range 3 {
+		if dslParamKeyword(typeId, i) == keyword && keyword != "to" {
+			return i
+		}
+	}
+	return -1
+}
+
+func parseDSLInt(value string) (int, error) {
+	out, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%w: expected number, got %q", DSLSyntaxError, value)
+	}
+	return out, nil
+}
+
+func parseDSLPoint(value string) (float64, float64, error) {
+	xs, ys, found := strings.Cut(value, ",")
+	x, xerr := strconv.ParseFloat(xs, 64)
+	y, yerr := strconv.ParseFloat(ys, 64)
+	if !found || xerr != nil || yerr != nil {
+		return 0, 0, fmt.Errorf("%w: expected X,Y, got %q", DSLSyntaxError, value)
+	}
+	return x, y, nil
+}
+
+/* Object type name in snake case, as used by the board description language. */
+func dslTypeName(typeId ObjectType) string {
+	label := strings.ReplaceAll(objectTypeLabel(typeId), "AoE", "Aoe")
+	if label == "" {
+		return fmt.Sprintf("object %d", typeId)
+	}
+	var out strings.Builder
+	separate := false
+	for _, r := range label {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			separate = true
+			continue
+		}
+		if (separate || unicode.IsUpper(r)) && out.Len() > 0 {
+			out.WriteRune('_')
+		}
+		separate = false
+		out.WriteRune(unicode.ToLower(r))
+	}
+	return out.String()
+}
+
+func formatDSLPoint(x float64, y float64) string {
+	return strconv.FormatFloat(x, 'f', -1, 64) + "," + strconv.FormatFloat(y, 'f', -1, 64)
+}
+
+/* Format strategy board in the board description language. */
+func FormatDSL(board Board) string {
+	var out strings.Builder
+	if board.Name != "" {
+		fmt.Fprintf(&out, "name %s\n", strconv.Quote(board.Name))
+	}
+	fmt.Fprintf(&out, "background %d\n", board.Background)
+	for _, object := range board.Objects {
+		out.WriteString(dslTypeName(object.TypeID))
+		if object.TypeID == ObjectTypeText {
+			fmt.Fprintf(&out, " %s", strconv.Quote(object.Text))
+		}
+		fmt.Fprintf(&out, " at %s", formatDSLPoint(object.PixelPosition()))
+		if object.Angle != 0 {
+			fmt.Fprintf(&out, " angle %d", object.Angle)
+		}
+		if object.Scale != DefaultObjectScale {
+			fmt.Fprintf(&out, " scale %d", object.Scale)
+		}
+		if object.Color != (color.NRGBA{255, 255, 255, 255}) {
+			fmt.Fprintf(&out, " color %s", FormatHexColor(object.Color))
+		}
+		if !object.Visible {
+			out.WriteString(" hidden")
+		}
+		if object.FlipHorizontal {
+			out.WriteString(" flip_horizontal")
+		}
+		if object.FlipVertical {
+			out.WriteString(" flip_vertical")
+		}
+		if object.Locked {
+			out.WriteString(" locked")
+		}
+		if object.RawFlags()&^knownObjectFlags != 0 {
+			fmt.Fprintf(&out, " flags %d", object.RawFlags())
+		}
+		defaults := defaultParams(object.TypeID)
+		for i, param := range object.Params {
+			if i >= len(defaults) || param == defaults[i] {
+				continue
+			}
+			if object.TypeID == ObjectTypeLine && i < 2 {
+				// line endpoint is written once for both params
+				if i == 0 || object.Params[0] == defaults[0] {
+					fmt.Fprintf(&out, " to %s", formatDSLPoint(NativeToCanvas(object.Params[0], object.Params[1], canvasWidth, canvasHeight)))
+				}
+				continue
+			}
+			fmt.Fprintf(&out, " %s %d", dslParamKeyword(object.TypeID, i), param)
+		}
+		out.WriteString("\n")
+	}
+	return out.String()
+}
@@ -0,0 +1,20 @@
+package strategy_board
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseDSLValidationErrorIndex(t *testing.T) {
+	input := "name \"Raid plan\"\ncircle_aoe at 200,200\ncircle_aoe at 300,300 scale 500\n"
+	_, err := ParseDSL(strings.NewReader(input))
+	var derr *DSLError
+	var verr *ValidationError
+	if !errors.As(err, &derr) || !errors.As(err, &verr) {
+		t.Fatalf("got %v, want validation error in DSL error", err)
+	}
+	if derr.Line != 3 || verr.Object != 1 || verr.Field != "scale" {
+		t.Errorf("got line %d object %d field %s, want line 3 object 1 field scale", derr.Line, verr.Object, verr.Field)
+	}
+}
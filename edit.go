@@ -0,0 +1,177 @@
+package strategy_board
+
+import (
+	"image/color"
+	"slices"
+)
+
+/*
+Board editing, each edit is checked against the board limits and leaves the board untouched when it fails.
+Objects are ordered front to back, index 0 is drawn on top of every other object. Locked objects can't be
+edited, removed, duplicated or moved in the layer order until they're unlocked.
+*/
+
+/* Check index refers to an object on the board. */
+func (b *Board) checkObjectIndex(index int) error {
+	if index < 0 || index >= len(b.Objects) {
+		return ObjectIndexError
+	}
+	return nil
+}
+
+/* Check index refers to an object on the board that isn't locked. */
+func (b *Board) checkObjectUnlocked(index int) error {
+	if err := b.checkObjectIndex(index); err != nil {
+		return err
+	}
+	if b.Objects[index].Locked {
+		return LockedObjectError
+	}
+	return nil
+}
+
+/* Apply an edit to a copy of the object and keep it only if it's within the board limits. */
+func (b *Board) editObject(index int, edit func(object *Object)) error {
+	if err := b.checkObjectUnlocked(index); err != nil {
+		return err
+	}
+	object := b.Objects[index]
+	object.Params = slices.Clone(object.Params)
+	edit(&object)
+	if err := checkObjectLimits(object); err != nil {
+		err.Object = index
+		return err
+	}
+	b.Objects[index] = object
+	return nil
+}
+
+/* Insert object at index in the layer order. */
+func (b *Board) InsertObject(index int, object Object) error {
+	if index < 0 || index > len(b.Objects) {
+		return ObjectIndexError
+	}
+	if len(b.Objects) >= MaxBoardObjects {
+		return &ValidationError{Object: -1, Field: "objects", Value: len(b.Objects) + 1, Err: ObjectCountLimitError}
+	}
+	if len(object.Params) < 3 {
+		object.Params = append(slices.Clone(object.Params), make([]int, 3-len(object.Params))...)
+	}
+	if err := checkObjectLimits(object); err != nil {
+		err.Object = index
+		return err
+	}
+	b.Objects = slices.Insert(b.Objects, index, object)
+	return nil
+}
+
+/* Add object in front of every other object. */
+func (b *Board) AddObject(object Object) error {
+	return b.InsertObject(0, object)
+}
+
+func (b *Board) RemoveObject(index int) error {
+	if err := b.checkObjectUnlocked(index); err != nil {
+		return err
+	}
+	b.Objects = slices.Delete(b.Objects, index, index+1)
+	return nil
+}
+
+/* Duplicate object, the copy is placed directly in front of the original and its index is returned. */
+func (b *Board) DuplicateObject(index int) (int, error) {
+	if err := b.checkObjectUnlocked(index); err != nil {
+		return -1, err
+	}
+	object := b.Objects[index]
+	object.Params = slices.Clone(object.Params)
+	if err := b.InsertObject(index, object); err != nil {
+		return -1, err
+	}
+	return index, nil
+}
+
+/* Move object to native board coordinates, a line's endpoint moves along with it. */
+func (b *Board) MoveObject(index int, x int, y int) error {
+	return b.editObject(index, func(object *Object) {
+		if object.TypeID == ObjectTypeLine && len(object.Params) >= 2 {
+			object.Params[0] += x - object.X
+			object.Params[1] += y - object.Y
+		}
+		object.X, object.Y = x, y
+	})
+}
+
+/* Set object rotation in degrees, normalized to -180 through 180. */
+func (b *Board) RotateObject(index int, angle int) error {
+	return b.editObject(index, func(object *Object) {
+		object.Angle = normalizeAngle(angle)
+	})
+}
+
+func (b *Board) SetObjectScale(index int, scale int) error {
+	return b.editObject(index, func(object *Object) {
+		object.Scale = scale
+	})
+}
+
+func (b *Board) SetObjectColor(index int, c color.NRGBA) error {
+	return b.editObject(index, func(object *Object) {
+		object.Color = c
+	})
+}
+
+func (b *Board) SetObjectFlip(index int, horizontal bool, vertical bool) error {
+	return b.editObject(index, func(object *Object) {
+		object.FlipHorizontal, object.FlipVertical = horizontal, vertical
+	})
+}
+
+func (b *Board) SetObjectVisible(index int, visible bool) error {
+	return b.editObject(index, func(object *Object) {
+		object.Visible = visible
+	})
+}
+
+/* Lock or unlock object, locked objects can't be edited until unlocked. */
+func (b *Board) SetObjectLocked(index int, locked bool) error {
+	if err := b.checkObjectIndex(index); err != nil {
+		return err
+	}
+	b.Objects[index].Locked = locked
+	return nil
+}
+
+/* Move object to another position in the layer order, the objects it moves past may be locked. */
+func (b *Board) ReorderObject(index int, newIndex int) error {
+	if err := b.checkObjectUnlocked(index); err != nil {
+		return err
+	}
+	if err := b.checkObjectIndex(newIndex); err != nil {
+		return err
+	}
+	object := b.Objects[index]
+	b.Objects = slices.Insert(slices.Delete(b.Objects, index, index+1), newIndex, object)
+	return nil
+}
+
+/* Move object one layer towards the front. */
+func (b *Board) RaiseObject(index int) error {
+	return b.ReorderObject(index, index-1)
+}
+
+/* Move object one layer towards the back. */
+func (b *Board) LowerObject(index int) error {
+	return b.ReorderObject(index, index+1)
+}
+
+/* Normalize angle in degrees to -180 through 180. */
+func normalizeAngle(angle int) int {
+	angle %= 360
+	if angle > 180 {
+		angle -= 360
+	} else if angle < -180 {
+		angle += 360
+	}
+	return angle
+}
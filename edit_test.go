@@ -0,0 +1,54 @@
+package strategy_board
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestLockedObjectEdits(t *testing.T) {
+	board := testBoards()[1]
+	locked := 4 // locked line
+	before := slices.Clone(board.Objects)
+
+	edits := map[string]func() error{
+		"move":      func() error { return board.MoveObject(locked, 100, 100) },
+		"remove":    func() error { return board.RemoveObject(locked) },
+		"duplicate": func() error { _, err := board.DuplicateObject(locked); return err },
+		"reorder":   func() error { return board.ReorderObject(locked, 0) },
+		"raise":     func() error { return board.RaiseObject(locked) },
+		"lower":     func() error { return board.LowerObject(locked) },
+	}
+	for name, edit := range edits {
+		if err := edit(); !errors.Is(err, LockedObjectError) {
+			t.Errorf("%s: got %v, want locked object error", name, err)
+		}
+	}
+	if len(board.Objects) != len(before) || board.Objects[locked].X != before[locked].X {
+		t.Errorf("board changed by edits of a locked object")
+	}
+
+	// other objects can still move past it, and it can be edited once unlocked
+	if err := board.RaiseObject(locked + 1); err != nil {
+		t.Errorf("raise past locked object: got %v, want no error", err)
+	}
+	if err := board.SetObjectLocked(locked+1, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := board.RemoveObject(locked + 1); err != nil {
+		t.Errorf("remove unlocked object: got %v, want no error", err)
+	}
+}
+
+func TestDuplicateObjectAtLimit(t *testing.T) {
+	board := testBoards()[1]
+	for len(board.Objects) < MaxBoardObjects {
+		if _, err := board.DuplicateObject(0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	index, err := board.DuplicateObject(0)
+	if !errors.Is(err, ObjectCountLimitError) || index != -1 {
+		t.Errorf("got %d, %v, want -1 and object count limit error", index, err)
+	}
+}
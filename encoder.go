@@ -0,0 +1,227 @@
+package strategy_board
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/binary"
+	"hash/crc32"
+	"log"
+	"math"
+)
+
+const headerLength = 24
+const defaultSeed = 0
+
+var backwardTranslationTable = func() map[rune]rune {
+	out := make(map[rune]rune, len(forwardTranslationTable))
+	for k, v := range forwardTranslationTable {
+		out[v] = k
+	}
+	return out
+}()
+
+/* Pack raw board bytes into a board share code, the inverse of Unpack. */
+func Pack(data []byte) (string, error) {
+	return packWithSeed(data, defaultSeed)
+}
+
+/* Pack raw board bytes into a share code scrambled with the given seed, 0 through 63. */
+func packWithSeed(data []byte, seed int) (string, error) {
+	log.Println("Pack strategy board")
+	if len(data) > math.MaxUint16 {
+		return "", EncodeValueRangeError
+	}
+
+	var compressed bytes.Buffer
+	z := zlib.NewWriter(&compressed)
+	if _, err := z.Write(data); err != nil {
+		return "", err
+	}
+	if err := z.Close(); err != nil {
+		return "", err
+	}
+
+	// 6 byte header, crc32 checksum of compressed data followed by uncompressed length
+	decoded := make([]byte, 6, 6+compressed.Len())
+	binary.LittleEndian.PutUint32(decoded, crc32.ChecksumIEEE(compressed.Bytes()))
+	binary.LittleEndian.PutUint16(decoded[4:], uint16(len(data)))
+	decoded = append(decoded, compressed.Bytes()...)
+
+	return scramble(decoded, seed), nil
+}
+
+/* Base64 encode header and compressed data and scramble them into a share code, the reverse of what Unpack does first. */
+func scramble(decoded []byte, seed int) string {
+	base64Str := base64.RawURLEncoding.EncodeToString(decoded)
+
+	buffer := make([]rune, 0, len(base64Str)+1)
+	buffer = append(buffer, backwardTranslateRune(mapOut(seed)))
+	for i, c := range base64Str {
+		x := (mapIn(c) + seed + i) & 0x3f
+		buffer = append(buffer, backwardTranslateRune(mapOut(x)))
+	}
+
+	return boardPrefix + string(buffer) + boardSuffix
+}
+
+/* Serialize strategy board to raw bytes, the inverse of Parse. Values that don't fit their field fail with EncodeValueRangeError. */
+func Serialize(board Board) ([]byte, error) {
+	log.Printf("Serialize strategy board with %d objects", len(board.Objects))
+	if len(board.Objects) > math.MaxUint16 {
+		return nil, EncodeValueRangeError
+	}
+	if err := checkEncodeRanges(board); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.Write(board.Raw.header())
+
+	// section 1, board name
+	writeUint16(&buf, 1)
+	if err := writeString(&buf, board.Name); err != nil {
+		return nil, err
+	}
+
+	// object type ids and object text
+	for _, object := range board.Objects {
+		writeUint16(&buf, 2)
+		writeUint16(&buf, int(object.TypeID))
+		if object.TypeID == ObjectTypeText {
+			writeUint16(&buf, 3)
+			if err := writeString(&buf, object.Text); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// object flags
+	writeSectionHeader(&buf, 4, board.Raw, len(board.Objects))
+	for _, object := range board.Objects {
+		writeUint16(&buf, int(object.RawFlags()))
+	}
+
+	// object coordinates
+	writeSectionHeader(&buf, 5, board.Raw, len(board.Objects))
+	for _, object := range board.Objects {
+		writeUint16(&buf, object.X)
+		writeUint16(&buf, object.Y)
+	}
+
+	// object angles
+	writeSectionHeader(&buf, 6, board.Raw, len(board.Objects))
+	for _, object := range board.Objects {
+		writeUint16(&buf, object.Angle)
+	}
+
+	// object scales
+	writeSectionHeader(&buf, 7, board.Raw, len(board.Objects))
+	for _, object := range board.Objects {
+		buf.WriteByte(byte(object.Scale))
+	}
+	buf.Write(board.Raw.scalePadding(len(board.Objects)))
+
+	// object colors, alpha is stored as transparency percentage
+	writeSectionHeader(&buf, 8, board.Raw, len(board.Objects))
+	for _, object := range board.Objects {
+		buf.WriteByte(object.Color.R)
+		buf.WriteByte(object.Color.G)
+		buf.WriteByte(object.Color.B)
+		buf.WriteByte(alphaToTransparency(object.Color.A))
+	}
+
+	// object params
+	for i, section := range []int{10, 11, 12} {
+		writeSectionHeader(&buf, section, board.Raw, len(board.Objects))
+		for _, object := range board.Objects {
+			param := 0
+			if i < len(object.Params) {
+				param = object.Params[i]
+			}
+			writeUint16(&buf, param)
+		}
+	}
+
+	// background
+	writeUint16(&buf, 3)
+	buf.Write(board.Raw.backgroundHeader())
+	writeUint16(&buf, board.Background)
+
+	// unknown trailing data
+	buf.Write(board.Raw.trailer())
+
+	return buf.Bytes(), nil
+}
+
+/* Encode strategy board as a share code. */
+func Encode(board Board) (string, error) {
+	log.Println("Encode strategy board")
+	data, err := Serialize(board)
+	if err != nil {
+		return "", err
+	}
+	return Pack(data)
+}
+
+/*
+Check every value fits the field it's stored in, so nothing is truncated and loading the share code
+gives back the same board.
+*/
+func checkEncodeRanges(board Board) error {
+	type encodeRange struct {
+		object   int
+		field    string
+		value    int
+		min, max int
+	}
+	ranges := []encodeRange{{-1, "background", board.Background, 0, math.MaxUint16}}
+	for i, object := range board.Objects {
+		ranges = append(ranges,
+			encodeRange{i, "type_id", int(object.TypeID), 0, math.MaxUint16},
+			encodeRange{i, "x", object.X, 0, math.MaxUint16},
+			encodeRange{i, "y", object.Y, 0, math.MaxUint16},
+			encodeRange{i, "angle", object.Angle, math.MinInt16, math.MaxInt16},
+			encodeRange{i, "scale", object.Scale, 0, math.MaxUint8},
+		)
+		// only three params are stored
+		ranges = append(ranges, encodeRange{i, "params", len(object.Params), 0, 3})
+		for _, param := range object.Params {
+			ranges = append(ranges, encodeRange{i, "params", param, math.MinInt16, math.MaxInt16})
+		}
+	}
+	for _, r := range ranges {
+		if r.value < r.min || r.value > r.max {
+			return &ValidationError{Object: r.object, Field: r.field, Value: r.value, Err: EncodeValueRangeError}
+		}
+	}
+	return nil
+}
+
+/* Convert alpha to the transparency percentage stored in board data. */
+func alphaToTransparency(alpha uint8) byte {
+	return byte(math.Round(100.0 * (1.0 - float64(alpha)/255.0)))
+}
+
+func writeSectionHeader(buf *bytes.Buffer, sectionNumber int, raw *RawBoardData, objectCount int) {
+	writeUint16(buf, sectionNumber)
+	buf.Write(raw.sectionHeader(sectionNumber))
+	writeUint16(buf, objectCount)
+}
+
+func backwardTranslateRune(c rune) rune {
+	return translateRune(c, backwardTranslationTable)
+}
+
+func writeUint16(buf *bytes.Buffer, value int) {
+	buf.Write(binary.LittleEndian.AppendUint16(nil, uint16(value)))
+}
+
+func writeString(buf *bytes.Buffer, value string) error {
+	if len(value) > math.MaxUint16 {
+		return EncodeValueRangeError
+	}
+	writeUint16(buf, len(value))
+	buf.WriteString(value)
+	return nil
+}
@@ -0,0 +1,75 @@
+package strategy_board
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSerializeParseRoundTrip(t *testing.T) {
+	for _, board := range testBoards() {
+		data, err := Serialize(board)
+		if err != nil {
+			t.Fatalf("serialize %q: %s", board.Name, err)
+		}
+		parsed, err := Parse(data)
+		if err != nil {
+			t.Fatalf("parse %q: %s", board.Name, err)
+		}
+		again, err := Serialize(parsed)
+		if err != nil {
+			t.Fatalf("serialize parsed %q: %s", board.Name, err)
+		}
+		if !bytes.Equal(data, again) {
+			t.Errorf("board %q changed after round trip:\n%x\n%x", board.Name, data, again)
+		}
+	}
+}
+
+func TestTransparencyRoundTrip(t *testing.T) {
+	for transparency := range 256 {
+		got := alphaToTransparency(transparencyToAlpha(byte(transparency)))
+		if want := byte(min(transparency, 100)); got != want {
+			t.Errorf("transparency %d: got %d, want %d", transparency, got, want)
+		}
+	}
+}
+
+func TestEncodeLoadRoundTrip(t *testing.T) {
+	for _, board := range testBoards() {
+		code, err := Encode(board)
+		if err != nil {
+			t.Fatalf("encode %q: %s", board.Name, err)
+		}
+		loaded, err := Load(code)
+		if err != nil {
+			t.Fatalf("load %q: %s", board.Name, err)
+		}
+		// undecoded regions come back from the board data, everything else should be unchanged
+		loaded.Raw = nil
+		if !reflect.DeepEqual(board, loaded) {
+			t.Errorf("board %q changed after round trip:\n%+v\n%+v", board.Name, board, loaded)
+		}
+	}
+}
+
+func TestSerializeValueRange(t *testing.T) {
+	edits := map[string]func(board *Board){
+		"negative x":             func(board *Board) { board.Objects[0].X = -5 },
+		"y past uint16":          func(board *Board) { board.Objects[0].Y = 70000 },
+		"scale past byte":        func(board *Board) { board.Objects[0].Scale = 300 },
+		"angle past int16":       func(board *Board) { board.Objects[0].Angle = 40000 },
+		"param past int16":       func(board *Board) { board.Objects[1].Params[0] = -40000 },
+		"fourth param":           func(board *Board) { board.Objects[1].Params = append(board.Objects[1].Params, 1) },
+		"negative type":          func(board *Board) { board.Objects[0].TypeID = -1 },
+		"background past uint16": func(board *Board) { board.Background = 1 << 16 },
+	}
+	for name, edit := range edits {
+		board := testBoards()[1]
+		edit(&board)
+		if _, err := Serialize(board); !errors.Is(err, EncodeValueRangeError) {
+			t.Errorf("%s: got %v, want value range error", name, err)
+		}
+	}
+}
@@ -1,12 +1,104 @@
 package strategy_board
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
-	MissingInput              = errors.New("missing strategy board input data")
-	ParseError                = errors.New("parse error: invalid strategy board")
-	SectionParseError         = errors.New("parse error: read unexpected section number")
-	ObjectCountParseError     = errors.New("parse error: unexpected number of objects in section")
-	DrawUnexpectedObjectError = errors.New("draw error: unexpected object type")
-	AssetNotFound             = errors.New("asset not found")
+	MissingInput                  = errors.New("missing strategy board input data")
+	ParseError                    = errors.New("parse error: invalid strategy board")
+	SectionParseError             = errors.New("parse error: read unexpected section number")
+	ObjectCountParseError         = errors.New("parse error: unexpected number of objects in section")
+	ChecksumParseError            = errors.New("parse error: share code checksum mismatch")
+	LengthParseError              = errors.New("parse error: share code length mismatch")
+	UnexpectedEndParseError       = errors.New("parse error: unexpected end of data")
+	EncodingParseError            = errors.New("parse error: invalid share code encoding")
+	CompressionParseError         = errors.New("parse error: invalid compressed board data")
+	ShareCodeLengthLimitError     = errors.New("limit error: share code too long")
+	DecompressedSizeLimitError    = errors.New("limit error: decompressed board data too large")
+	ObjectCountLimitError         = errors.New("limit error: too many objects")
+	TextLengthLimitError          = errors.New("limit error: text too long")
+	ParamsObjectTypeError         = errors.New("params error: object type does not have these params")
+	MissingParamsError            = errors.New("params error: object is missing params")
+	UnknownObjectTypeError        = errors.New("validation error: unknown object type")
+	ValueRangeError               = errors.New("validation error: value out of range")
+	InvalidColorError             = errors.New("json error: invalid color, expected #RRGGBBAA")
+	UnknownParamError             = errors.New("json error: unknown param for object type")
+	UnsupportedFormatVersionError = errors.New("json error: unsupported format version")
+	MissingFormatVersionError     = errors.New("json error: missing format_version")
+	DSLSyntaxError                = errors.New("dsl error: syntax error")
+	ObjectIndexError              = errors.New("edit error: no object at index")
+	LockedObjectError             = errors.New("edit error: object is locked")
+	DrawUnexpectedObjectError     = errors.New("draw error: unexpected object type")
+	DrawOptionsError              = errors.New("draw error: invalid draw options")
+	AssetNotFound                 = errors.New("asset not found")
+	EncodeValueRangeError         = errors.New("encode error: value out of range")
 )
+
+/*
+Parse error with details on where in the data it occurred. Wraps one of the parse error sentinels above,
+along with the underlying decoding error if there is one. Offset is into the share code for prefix and
+encoding errors and into the decoded data otherwise.
+*/
+type BoardParseError struct {
+	Err      error
+	Section  int
+	Offset   int
+	Expected any
+	Actual   any
+	Cause    error
+}
+
+func (e *BoardParseError) Error() string {
+	out := fmt.Sprintf("%s: ", e.Err)
+	if e.Section > 0 {
+		out += fmt.Sprintf("section %d at ", e.Section)
+	}
+	out += fmt.Sprintf("offset 0x%X", e.Offset)
+	if e.Cause != nil {
+		return out + fmt.Sprintf(": %s", e.Cause)
+	}
+	if e.Expected == nil && e.Actual == nil {
+		return out
+	}
+	switch e.Err {
+	case SectionParseError:
+		return out + fmt.Sprintf(": expected section %v, got %v", e.Expected, e.Actual)
+	case ObjectCountParseError:
+		return out + fmt.Sprintf(": expected %v objects, got %v", e.Expected, e.Actual)
+	case ChecksumParseError:
+		return out + fmt.Sprintf(": expected checksum 0x%08X, got 0x%08X", e.Expected, e.Actual)
+	case ShareCodeLengthLimitError, DecompressedSizeLimitError, ObjectCountLimitError, TextLengthLimitError:
+		return out + fmt.Sprintf(": limit %v, got %v", e.Expected, e.Actual)
+	case LengthParseError, UnexpectedEndParseError:
+		return out + fmt.Sprintf(": expected %v bytes, got %v", e.Expected, e.Actual)
+	}
+	return out + fmt.Sprintf(": expected %v, got %v", e.Expected, e.Actual)
+}
+
+func (e *BoardParseError) Unwrap() []error {
+	if e.Cause != nil {
+		return []error{e.Err, e.Cause}
+	}
+	return []error{e.Err}
+}
+
+/* Board or object value that fails validation, Object is -1 for board level fields. */
+type ValidationError struct {
+	Object int
+	Field  string
+	Value  int
+	Err    error
+}
+
+func (e *ValidationError) Error() string {
+	if e.Object < 0 {
+		return fmt.Sprintf("%s: %s is %d", e.Err, e.Field, e.Value)
+	}
+	return fmt.Sprintf("%s: object %d %s is %d", e.Err, e.Object, e.Field, e.Value)
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,61 @@
+package strategy_board
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+/* What to leave out of a board's canonical form. */
+type CanonicalOptions struct {
+	IgnoreName   bool
+	IgnoreHidden bool
+}
+
+/*
+Canonical form of board, boards that look the same in game have the same canonical form.
+Undecoded raw data is dropped, the name's padding is trimmed, params are padded to the three
+the board data stores and angles are normalized.
+*/
+func (b Board) Canonical() Board {
+	return b.CanonicalWithOptions(CanonicalOptions{})
+}
+
+/* Canonical form of board, optionally leaving out its name and hidden objects. */
+func (b Board) CanonicalWithOptions(options CanonicalOptions) Board {
+	out := Board{Name: strings.TrimRight(b.Name, "\x00"), Background: b.Background, Objects: make([]Object, 0, len(b.Objects))}
+	if options.IgnoreName {
+		out.Name = ""
+	}
+	for _, object := range b.Objects {
+		if options.IgnoreHidden && !object.Visible {
+			continue
+		}
+		object.Flags = object.RawFlags()
+		object.Params = paddedParams(object.Params)[:3]
+		object.Angle = normalizeAngle(object.Angle)
+		if object.Angle == -180 {
+			object.Angle = 180
+		}
+		if object.TypeID != ObjectTypeText {
+			object.Text = ""
+		}
+		out.Objects = append(out.Objects, object)
+	}
+	return out
+}
+
+/* SHA-256 of the board's canonical form as hex, stable across share codes of the same board. */
+func (b Board) Hash() (string, error) {
+	return b.HashWithOptions(CanonicalOptions{})
+}
+
+/* SHA-256 of the board's canonical form as hex, optionally ignoring its name and hidden objects. */
+func (b Board) HashWithOptions(options CanonicalOptions) (string, error) {
+	data, err := Serialize(b.CanonicalWithOptions(options))
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,67 @@
+package strategy_board
+
+import "testing"
+
+func TestHashStableAcrossSeeds(t *testing.T) {
+	board := testBoards()[1]
+	want, err := board.Hash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := Serialize(board)
+	if err != nil {
+		t.Fatal(err)
+	}
+	codes := make(map[string]int)
+	for _, seed := range []int{0, 1, 17, 63} {
+		code, err := packWithSeed(data, seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if other, ok := codes[code]; ok {
+			t.Errorf("seeds %d and %d give the same share code, want different ones", other, seed)
+		}
+		codes[code] = seed
+		loaded, err := Load(code)
+		if err != nil {
+			t.Fatalf("seed %d: %s", seed, err)
+		}
+		if got, err := loaded.Hash(); err != nil || got != want {
+			t.Errorf("seed %d: got hash %s, %v, want %s", seed, got, err, want)
+		}
+	}
+}
+
+func TestHashOptions(t *testing.T) {
+	hidden := 5 // hidden tank
+	tests := []struct {
+		name    string
+		edit    func(board *Board)
+		options CanonicalOptions
+		changed bool
+	}{
+		{"rename", func(b *Board) { b.Name = "Other plan" }, CanonicalOptions{}, true},
+		{"rename ignoring name", func(b *Board) { b.Name = "Other plan" }, CanonicalOptions{IgnoreName: true}, false},
+		{"name padding", func(b *Board) { b.Name += "\x00\x00" }, CanonicalOptions{}, false},
+		{"move hidden", func(b *Board) { b.Objects[hidden].X += 10 }, CanonicalOptions{}, true},
+		{"move hidden ignoring hidden", func(b *Board) { b.Objects[hidden].X += 10 }, CanonicalOptions{IgnoreHidden: true}, false},
+		{"move visible ignoring hidden", func(b *Board) { b.Objects[0].X += 10 }, CanonicalOptions{IgnoreHidden: true}, true},
+		{"move visible ignoring name", func(b *Board) { b.Objects[0].X += 10 }, CanonicalOptions{IgnoreName: true}, true},
+		{"equivalent angle", func(b *Board) { b.Objects[1].Angle += 360 }, CanonicalOptions{}, false},
+	}
+	for _, test := range tests {
+		board := testBoards()[1]
+		before, err := board.HashWithOptions(test.options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		test.edit(&board)
+		after, err := board.HashWithOptions(test.options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if changed := before != after; changed != test.changed {
+			t.Errorf("%s: hash changed is %t, want %t", test.name, changed, test.changed)
+		}
+	}
+}
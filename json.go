@@ -0,0 +1,270 @@
+package strategy_board
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"log"
+	"strconv"
+	"strings"
+)
+
+/*
+Version of the board JSON format, bumped whenever a change would break existing readers.
+
+Format version 1:
+  - format_version, name, background and objects at the top level
+  - type_id and type, the object type's name, on every object
+  - x and y in native board coordinates (5120x3840)
+  - colors as #RRGGBBAA
+  - params as an object labelled per object type, see ParamLabels
+*/
+const JSONFormatVersion = 1
+
+/* JSON Schema describing the board JSON format. */
+//go:embed board.schema.json
+var JSONSchema []byte
+
+/* Board as stored in JSON, pointers mark fields that get a default or are required when missing. */
+type jsonBoard struct {
+	FormatVersion *int          `json:"format_version"`
+	Name          string        `json:"name"`
+	Background    *int          `json:"background,omitempty"`
+	Objects       []jsonObject  `json:"objects"`
+	Raw           *RawBoardData `json:"raw,omitempty"`
+	// objects of JSON written before format version 1, only read to reject it
+	LegacyObjects json.RawMessage `json:"object,omitempty"`
+}
+
+type jsonObject struct {
+	TypeID         *ObjectType     `json:"type_id,omitempty"`
+	Type           string          `json:"type,omitempty"`
+	Text           string          `json:"text,omitempty"`
+	Visible        *bool           `json:"visible,omitempty"`
+	FlipHorizontal bool            `json:"flip_horizontal"`
+	FlipVertical   bool            `json:"flip_vertical"`
+	Locked         bool            `json:"locked"`
+	Flags          BoardObjectFlag `json:"flags"`
+	X              int             `json:"x"`
+	Y              int             `json:"y"`
+	Angle          int             `json:"angle"`
+	Color          string          `json:"color,omitempty"`
+	Scale          *int            `json:"scale,omitempty"`
+	Params         map[string]int  `json:"params,omitempty"`
+}
+
+/* Labels of the params stored for the given object type, params past these are labelled param1, param2 and so on. */
+func ParamLabels(typeId ObjectType) []string {
+	switch typeId {
+	case ObjectTypeCircleAoE, ObjectTypeDonut:
+		return []string{"angle", "inner_radius"}
+	case ObjectTypeLineAoE:
+		return []string{"half_width", "half_height"}
+	case ObjectTypeLine:
+		return []string{"end_x", "end_y", "thickness"}
+	}
+	return nil
+}
+
+func paramLabel(typeId ObjectType, index int) string {
+	if labels := ParamLabels(typeId); index < len(labels) {
+		return labels[index]
+	}
+	return fmt.Sprintf("param%d", index+1)
+}
+
+/* Format color as #RRGGBBAA. */
+func FormatHexColor(c color.NRGBA) string {
+	return fmt.Sprintf("#%02X%02X%02X%02X", c.R, c.G, c.B, c.A)
+}
+
+/* Parse color from #RRGGBBAA or #RRGGBB. */
+func ParseHexColor(value string) (color.NRGBA, error) {
+	hex, ok := strings.CutPrefix(value, "#")
+	if !ok || (len(hex) != 6 && len(hex) != 8) {
+		return color.NRGBA{}, fmt.Errorf("%w: %q", InvalidColorError, value)
+	}
+	if len(hex) == 6 {
+		hex += "FF"
+	}
+	rgba, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.NRGBA{}, fmt.Errorf("%w: %q", InvalidColorError, value)
+	}
+	return color.NRGBA{uint8(rgba >> 24), uint8(rgba >> 16), uint8(rgba >> 8), uint8(rgba)}, nil
+}
+
+func (b Board) MarshalJSON() ([]byte, error) {
+	version := JSONFormatVersion
+	out := jsonBoard{
+		FormatVersion: &version,
+		Name:          b.Name,
+		Background:    &b.Background,
+		Objects:       make([]jsonObject, 0, len(b.Objects)),
+		Raw:           b.Raw,
+	}
+	for _, o := range b.Objects {
+		out.Objects = append(out.Objects, o.toJSON())
+	}
+	return json.Marshal(out)
+}
+
+func (o Object) toJSON() jsonObject {
+	out := jsonObject{
+		TypeID:         &o.TypeID,
+		Type:           objectTypeLabel(o.TypeID),
+		Text:           o.Text,
+		Visible:        &o.Visible,
+		FlipHorizontal: o.FlipHorizontal,
+		FlipVertical:   o.FlipVertical,
+		Locked:         o.Locked,
+		Flags:          o.RawFlags(),
+		X:              o.X,
+		Y:              o.Y,
+		Angle:          o.Angle,
+		Color:          FormatHexColor(o.Color),
+		Scale:          &o.Scale,
+		Params:         make(map[string]int),
+	}
+	labels := ParamLabels(o.TypeID)
+	for i, param := range o.Params {
+		// params without a meaning for this object type are only kept if set
+		if i < len(labels) || param != 0 {
+			out.Params[paramLabel(o.TypeID, i)] = param
+		}
+	}
+	return out
+}
+
+func (o Object) MarshalJSON() ([]byte, error) {
+	return json.Marshal(o.toJSON())
+}
+
+/* Decode board from JSON, filling in defaults for missing fields. The format version is required. */
+func (b *Board) UnmarshalJSON(data []byte) error {
+	in := jsonBoard{}
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	switch {
+	case in.FormatVersion == nil && in.LegacyObjects != nil:
+		return fmt.Errorf("%w, objects under \"object\" with pixel coordinates are from before format version 1", MissingFormatVersionError)
+	case in.FormatVersion == nil:
+		return MissingFormatVersionError
+	case *in.FormatVersion < 1 || *in.FormatVersion > JSONFormatVersion:
+		return fmt.Errorf("%w: %d", UnsupportedFormatVersionError, *in.FormatVersion)
+	}
+
+	*b = Board{Name: in.Name, Background: DefaultBackground, Objects: make([]Object, 0, len(in.Objects)), Raw: in.Raw}
+	if in.Background != nil {
+		b.Background = *in.Background
+	}
+	for i, o := range in.Objects {
+		object, err := o.toObject()
+		if err != nil {
+			if verr, ok := err.(*ValidationError); ok {
+				verr.Object = i
+			}
+			return err
+		}
+		b.Objects = append(b.Objects, object)
+	}
+	return nil
+}
+
+func (o jsonObject) toObject() (Object, error) {
+	// type id takes precedence over type name
+	var typeId ObjectType
+	switch {
+	case o.TypeID != nil:
+		typeId = *o.TypeID
+	case o.Type != "":
+		var found bool
+		var err error
+		if typeId, found, err = objectTypeByLabel(o.Type); err != nil {
+			return Object{}, err
+		} else if !found {
+			return Object{}, &ValidationError{Field: "type", Err: UnknownObjectTypeError}
+		}
+	default:
+		return Object{}, &ValidationError{Field: "type_id", Err: UnknownObjectTypeError}
+	}
+
+	object := Object{
+		TypeID:         typeId,
+		Text:           o.Text,
+		Visible:        true,
+		FlipHorizontal: o.FlipHorizontal,
+		FlipVertical:   o.FlipVertical,
+		Locked:         o.Locked,
+		Flags:          o.Flags,
+		X:              o.X,
+		Y:              o.Y,
+		Angle:          o.Angle,
+		Color:          color.NRGBA{255, 255, 255, 255},
+		Scale:          DefaultObjectScale,
+		Params:         defaultParams(typeId),
+	}
+	if o.Visible != nil {
+		object.Visible = *o.Visible
+	}
+	if o.Color != "" {
+		var err error
+		if object.Color, err = ParseHexColor(o.Color); err != nil {
+			return Object{}, err
+		}
+	}
+	if o.Scale != nil {
+		object.Scale = *o.Scale
+	}
+	for label, value := range o.Params {
+		index := -1
+		for i := range object.Params {
+			if paramLabel(typeId, i) == label {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			return Object{}, fmt.Errorf("%w: %q", UnknownParamError, label)
+		}
+		object.Params[index] = value
+	}
+	return object, nil
+}
+
+func (o *Object) UnmarshalJSON(data []byte) error {
+	in := jsonObject{}
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	object, err := in.toObject()
+	if err != nil {
+		return err
+	}
+	*o = object
+	return nil
+}
+
+/* Parse strategy board from JSON, filling in defaults and validating it against the board limits. */
+func ParseJSON(data []byte) (Board, error) {
+	log.Printf("Parse %d byte strategy board JSON", len(data))
+	board := Board{}
+	if err := json.Unmarshal(data, &board); err != nil {
+		return Board{}, err
+	}
+	if err := checkBoardLimits(board); err != nil {
+		return Board{}, err
+	}
+	return board, nil
+}
+
+/* Default params of the three the board data stores, arcs default to a full circle. */
+func defaultParams(typeId ObjectType) []int {
+	out := make([]int, 3)
+	if typeId == ObjectTypeCircleAoE || typeId == ObjectTypeDonut {
+		out[0] = DefaultArcAngle
+	}
+	return out
+}
@@ -0,0 +1,65 @@
+package strategy_board
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestJSONRoundTrip(t *testing.T) {
+	boards := testBoards()
+	// a board loaded from a share code also carries its undecoded data
+	code, err := Encode(boards[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	boards = append(boards, loaded)
+
+	for _, board := range boards {
+		data, err := json.Marshal(board)
+		if err != nil {
+			t.Fatalf("marshal %q: %s", board.Name, err)
+		}
+		parsed, err := ParseJSON(data)
+		if err != nil {
+			t.Fatalf("parse %q: %s\n%s", board.Name, err, data)
+		}
+		if !reflect.DeepEqual(board, parsed) {
+			t.Errorf("board %q changed after round trip:\n%+v\n%+v", board.Name, board, parsed)
+		}
+	}
+}
+
+func TestParseJSONFormatVersion(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  error
+	}{
+		{"current", `{"format_version": 1, "objects": [{"type": "circle_aoe", "x": 2560, "y": 1920}]}`, nil},
+		{"missing", `{"objects": [{"type": "circle_aoe", "x": 2560, "y": 1920}]}`, MissingFormatVersionError},
+		// written by -output json before the format was versioned
+		{
+			"legacy",
+			`{"name": "", "background": 1, "object": [{"type_id": 10, "text": "", "visible": true, "x": 512, "y": 384, "angle": 0, "color": {"R": 255, "G": 255, "B": 255, "A": 255}, "scale": 100, "params": [360, 0, 0]}]}`,
+			MissingFormatVersionError,
+		},
+		{"zero", `{"format_version": 0, "objects": []}`, UnsupportedFormatVersionError},
+		{"newer", `{"format_version": 2, "objects": []}`, UnsupportedFormatVersionError},
+	}
+	for _, test := range tests {
+		board, err := ParseJSON([]byte(test.input))
+		if test.want == nil {
+			if err != nil || len(board.Objects) != 1 {
+				t.Errorf("%s: got %+v, %v, want one object", test.name, board, err)
+			}
+		} else if !errors.Is(err, test.want) {
+			t.Errorf("%s: got %v, want %v", test.name, err, test.want)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package strategy_board
+
+import (
+	"math"
+	"unicode/utf8"
+)
+
+/*
+Limits this library checks in JSON and DSL import, edits, transforms and Validate. Where a limit comes from is
+noted on each, the share code format itself allows larger values for all of them. The in-game editor limits
+haven't been confirmed against the game in this repository, adjust them here if the game turns out to differ.
+Name and text lengths are counted in characters, not bytes. MaxLineAoEHalfSize and MaxLineThickness aren't
+game limits, so Validate only warns about values past them and import accepts anything the format stores.
+*/
+const (
+	// in-game editor, the format stores a 16 bit object count
+	MaxBoardObjects = 50
+	// in-game editor, the format stores a 16 bit byte length
+	MaxBoardNameLength = 20
+	// in-game editor, the format stores a 16 bit byte length
+	MaxTextLength = 30
+	// in-game editor scale range in percent, the format stores a byte
+	MinObjectScale = 50
+	MaxObjectScale = 200
+	// rotation in degrees, half a turn either way
+	MinObjectAngle = -180
+	MaxObjectAngle = 180
+	// backgrounds packed by tools/asset_compiler, x1.png through x7.png
+	MinBackground = 1
+	MaxBackground = 7
+	// a full circle, in degrees
+	MaxArcAngle = 360
+	// outer radius of a circle AoE as drawn by drawArc, an inner radius past it leaves nothing to draw
+	MaxArcInnerRadius = 256
+	// not a game limit, the canvas width so a line AoE can span the whole board, only warned about
+	MaxLineAoEHalfSize = 1024
+	// not a game limit, a cap well past any line drawn in the editor, only warned about
+	MaxLineThickness = 64
+)
+
+/* Defaults for fields missing from imported boards. */
+const (
+	DefaultObjectScale = 100
+	DefaultBackground  = 1
+	DefaultArcAngle    = 360
+)
+
+/* Check a board against the limits above, returning the first problem found. */
+func checkBoardLimits(board Board) error {
+	if len(board.Objects) > MaxBoardObjects {
+		return &ValidationError{Object: -1, Field: "objects", Value: len(board.Objects), Err: ObjectCountLimitError}
+	}
+	if length := utf8.RuneCountInString(board.Name); length > MaxBoardNameLength {
+		return &ValidationError{Object: -1, Field: "name", Value: length, Err: TextLengthLimitError}
+	}
+	if board.Background < MinBackground || board.Background > MaxBackground {
+		return &ValidationError{Object: -1, Field: "background", Value: board.Background, Err: ValueRangeError}
+	}
+	for i, object := range board.Objects {
+		if err := checkObjectLimits(object); err != nil {
+			err.Object = i
+			return err
+		}
+	}
+	return nil
+}
+
+/* Check an object against the limits above. */
+func checkObjectLimits(object Object) *ValidationError {
+	known, err := isKnownObjectType(object.TypeID)
+	if err != nil {
+		return &ValidationError{Field: "type_id", Value: int(object.TypeID), Err: err}
+	}
+	if !known {
+		return &ValidationError{Field: "type_id", Value: int(object.TypeID), Err: UnknownObjectTypeError}
+	}
+	if length := utf8.RuneCountInString(object.Text); length > MaxTextLength {
+		return &ValidationError{Field: "text", Value: length, Err: TextLengthLimitError}
+	}
+	for _, check := range []struct {
+		field    string
+		value    int
+		min, max int
+	}{
+		{"x", object.X, 0, BoardWidth},
+		{"y", object.Y, 0, BoardHeight},
+		{"angle", object.Angle, MinObjectAngle, MaxObjectAngle},
+		{"scale", object.Scale, MinObjectScale, MaxObjectScale},
+	} {
+		if check.value < check.min || check.value > check.max {
+			return &ValidationError{Field: check.field, Value: check.value, Err: ValueRangeError}
+		}
+	}
+	return checkObjectParams(object)
+}
+
+/* Check params of objects that use them, line AoE sizes and line thickness only need to fit the format. */
+func checkObjectParams(object Object) *ValidationError {
+	type paramRange struct{ min, max int }
+	var ranges []paramRange
+	switch object.TypeID {
+	case ObjectTypeCircleAoE, ObjectTypeDonut:
+		ranges = []paramRange{{0, MaxArcAngle}, {0, MaxArcInnerRadius}}
+	case ObjectTypeLineAoE:
+		ranges = []paramRange{{0, math.MaxInt16}, {0, math.MaxInt16}}
+	case ObjectTypeLine:
+		ranges = []paramRange{{0, BoardWidth}, {0, BoardHeight}, {0, math.MaxInt16}}
+	}
+	if len(object.Params) < len(ranges) {
+		return &ValidationError{Field: "params", Value: len(object.Params), Err: MissingParamsError}
+	}
+	for i, r := range ranges {
+		if object.Params[i] < r.min || object.Params[i] > r.max {
+			return &ValidationError{Field: "params", Value: object.Params[i], Err: ValueRangeError}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package strategy_board
+
+import (
+	"errors"
+	"image/color"
+	"strings"
+	"testing"
+)
+
+func TestLimitsCountCharacters(t *testing.T) {
+	text := Object{TypeID: ObjectTypeText, Visible: true, X: 2560, Y: 1920, Scale: 100, Color: color.NRGBA{255, 255, 255, 255}, Params: []int{0, 0, 0}}
+
+	// multi-byte names and text within the limit in characters pass
+	text.Text = strings.Repeat("あ", MaxTextLength)
+	board := Board{Name: "ボードの名前です", Background: 1, Objects: []Object{text}}
+	if err := checkBoardLimits(board); err != nil {
+		t.Errorf("got %v, want no error", err)
+	}
+	if issues := Validate(board); HasErrors(issues) {
+		t.Errorf("got issues %v, want none", issues)
+	}
+
+	// one character more fails, with the length in characters
+	board.Objects[0].Text += "あ"
+	var verr *ValidationError
+	if err := checkBoardLimits(board); !errors.As(err, &verr) || verr.Field != "text" || verr.Value != MaxTextLength+1 {
+		t.Errorf("got %v, want text length %d error", err, MaxTextLength+1)
+	}
+	board.Objects[0].Text = ""
+	board.Name = strings.Repeat("名", MaxBoardNameLength+1)
+	if err := checkBoardLimits(board); !errors.As(err, &verr) || verr.Field != "name" || verr.Value != MaxBoardNameLength+1 {
+		t.Errorf("got %v, want name length %d error", err, MaxBoardNameLength+1)
+	}
+	if issues := Validate(board); !HasErrors(issues) {
+		t.Errorf("got no errors, want name length error")
+	}
+}
+
+func TestLineSizeWarnings(t *testing.T) {
+	line := Object{TypeID: ObjectTypeLine, Visible: true, X: 100, Y: 100, Scale: 100, Color: color.NRGBA{255, 255, 255, 255}, Params: []int{500, 500, MaxLineThickness + 1}}
+	lineAoE := Object{TypeID: ObjectTypeLineAoE, Visible: true, X: 2560, Y: 1920, Scale: 100, Color: color.NRGBA{255, 255, 255, 255}, Params: []int{MaxLineAoEHalfSize + 1, 10, 0}}
+	for _, object := range []Object{line, lineAoE} {
+		board := Board{Background: 1, Objects: []Object{object}}
+		// past the library's caps is still a valid board, only Validate warns about it
+		if err := checkBoardLimits(board); err != nil {
+			t.Errorf("type %d: got %v, want no error", object.TypeID, err)
+		}
+		issues := Validate(board)
+		if HasErrors(issues) || len(issues) != 1 || issues[0].Severity != SeverityWarning {
+			t.Errorf("type %d: got issues %v, want one warning", object.TypeID, issues)
+		}
+		// negative sizes are still errors
+		board.Objects[0].Params = append([]int{}, object.Params...)
+		board.Objects[0].Params[0] = -1
+		if err := checkBoardLimits(board); !errors.Is(err, ValueRangeError) {
+			t.Errorf("type %d: got %v, want value range error", object.TypeID, err)
+		}
+	}
+}
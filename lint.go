@@ -0,0 +1,108 @@
+package strategy_board
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+/* Problem found on a board, object is -1 for problems with the board itself. */
+type Issue struct {
+	Severity Severity `json:"severity"`
+	Object   int      `json:"object"`
+	Message  string   `json:"message"`
+}
+
+func (i Issue) String() string {
+	if i.Object < 0 {
+		return fmt.Sprintf("%s: board: %s", i.Severity, i.Message)
+	}
+	return fmt.Sprintf("%s: object %d: %s", i.Severity, i.Object, i.Message)
+}
+
+/* Whether any issue is an error, as opposed to a warning. */
+func HasErrors(issues []Issue) bool {
+	for _, issue := range issues {
+		if issue.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+/* Check board against the limits in limits.go and for problems that would make Draw fail, along with likely mistakes. */
+func Validate(board Board) []Issue {
+	issues := make([]Issue, 0)
+	boardIssue := func(severity Severity, format string, args ...any) {
+		issues = append(issues, Issue{severity, -1, fmt.Sprintf(format, args...)})
+	}
+
+	if len(board.Objects) > MaxBoardObjects {
+		boardIssue(SeverityError, "%d objects, over the limit of %d", len(board.Objects), MaxBoardObjects)
+	}
+	if length := utf8.RuneCountInString(board.Name); length > MaxBoardNameLength {
+		boardIssue(SeverityError, "name is %d characters long, over the limit of %d", length, MaxBoardNameLength)
+	}
+	if board.Background < MinBackground || board.Background > MaxBackground {
+		boardIssue(SeverityError, "unknown background %d, expected %d through %d", board.Background, MinBackground, MaxBackground)
+	}
+
+	for i, object := range board.Objects {
+		objectIssue := func(severity Severity, format string, args ...any) {
+			issues = append(issues, Issue{severity, i, fmt.Sprintf(format, args...)})
+		}
+		description := objectTypeDescription(object.TypeID)
+
+		known, err := isKnownObjectType(object.TypeID)
+		if err != nil {
+			boardIssue(SeverityError, "could not load asset list: %s", err)
+			return issues
+		}
+		if !known {
+			objectIssue(SeverityError, "unknown object type %d, drawing it fails with %q", object.TypeID, AssetNotFound)
+		}
+
+		if object.X < 0 || object.X > BoardWidth || object.Y < 0 || object.Y > BoardHeight {
+			x, y := object.PixelPosition()
+			objectIssue(SeverityError, "%s at %s is outside the %dx%d canvas", description, formatDSLPoint(x, y), canvasWidth, canvasHeight)
+		}
+		if object.Scale < MinObjectScale || object.Scale > MaxObjectScale {
+			objectIssue(SeverityError, "scale %d is outside %d through %d", object.Scale, MinObjectScale, MaxObjectScale)
+		}
+		if object.Angle < MinObjectAngle || object.Angle > MaxObjectAngle {
+			objectIssue(SeverityError, "angle %d is outside %d through %d", object.Angle, MinObjectAngle, MaxObjectAngle)
+		}
+
+		if object.TypeID == ObjectTypeText {
+			if object.Text == "" {
+				objectIssue(SeverityWarning, "text object has no text and won't be drawn")
+			} else if length := utf8.RuneCountInString(object.Text); length > MaxTextLength {
+				objectIssue(SeverityError, "text is %d characters long, over the limit of %d", length, MaxTextLength)
+			}
+		}
+
+		if err := checkObjectParams(object); err != nil {
+			switch err.Err {
+			case MissingParamsError:
+				objectIssue(SeverityError, "%s is missing params, has %d", description, len(object.Params))
+			default:
+				objectIssue(SeverityError, "%s has param value %d out of range", description, err.Value)
+			}
+		} else if object.TypeID == ObjectTypeLineAoE && (object.Params[0] > MaxLineAoEHalfSize || object.Params[1] > MaxLineAoEHalfSize) {
+			objectIssue(SeverityWarning, "%s half size %dx%d is past %d, the canvas width", description, object.Params[0], object.Params[1], MaxLineAoEHalfSize)
+		} else if object.TypeID == ObjectTypeLine && object.Params[2] > MaxLineThickness {
+			objectIssue(SeverityWarning, "line thickness %d is past %d, wider than any line drawn in the editor", object.Params[2], MaxLineThickness)
+		}
+
+		if !object.Visible {
+			objectIssue(SeverityWarning, "%s is hidden and may have been forgotten", description)
+		}
+	}
+	return issues
+}
@@ -0,0 +1,40 @@
+package strategy_board
+
+import (
+	"image/color"
+	"io"
+	"log"
+	"os"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+/* Boards covering every kind of object, used across tests, with flags as Parse sets them. */
+func testBoards() []Board {
+	boards := []Board{
+		{Name: "", Background: 1, Objects: []Object{}},
+		{
+			Name:       "Raid plan",
+			Background: 3,
+			Objects: []Object{
+				{TypeID: ObjectTypeText, Text: "Stack here", Visible: true, X: 2560, Y: 500, Scale: 100, Color: color.NRGBA{255, 255, 255, 255}, Params: []int{0, 0, 0}},
+				{TypeID: ObjectTypeCircleAoE, Visible: true, X: 1500, Y: 1500, Angle: 30, Scale: 150, Color: color.NRGBA{255, 255, 255, 128}, Params: []int{90, 0, 0}},
+				{TypeID: ObjectTypeDonut, Visible: true, FlipHorizontal: true, X: 3500, Y: 1500, Angle: -45, Scale: 80, Color: color.NRGBA{255, 255, 255, 255}, Params: []int{270, 100, 0}},
+				{TypeID: ObjectTypeLineAoE, Visible: true, X: 2500, Y: 3000, Angle: 20, Scale: 100, Color: color.NRGBA{48, 128, 255, 204}, Params: []int{100, 30, 0}},
+				{TypeID: ObjectTypeLine, Visible: true, Locked: true, X: 500, Y: 3500, Scale: 100, Color: color.NRGBA{255, 48, 48, 255}, Params: []int{1500, 2500, 6}},
+				{TypeID: 47, Visible: false, FlipVertical: true, X: 4000, Y: 3000, Angle: 180, Scale: 200, Color: color.NRGBA{255, 255, 255, 255}, Params: []int{0, 0, 0}},
+				{TypeID: 48, Visible: true, Flags: 0x100, X: 0, Y: 0, Angle: -180, Scale: 50, Color: color.NRGBA{0, 0, 0, 0}, Params: []int{0, 0, 0}},
+			},
+		},
+	}
+	for _, board := range boards {
+		for i := range board.Objects {
+			board.Objects[i].Flags = board.Objects[i].RawFlags()
+		}
+	}
+	return boards
+}
@@ -0,0 +1,86 @@
+package strategy_board
+
+import "fmt"
+
+//go:generate go run ./tools/object_types_gen
+
+/*
+Strategy board object type id. Objects drawn without a sprite have constants below, constants, names and
+categories of sprite objects are generated into object_types_gen.go from the asset list in assets.zip.
+*/
+type ObjectType int
+
+const (
+	ObjectTypeCircleAoE ObjectType = 10
+	ObjectTypeLineAoE   ObjectType = 11
+	ObjectTypeLine      ObjectType = 12
+	ObjectTypeDonut     ObjectType = 17
+	ObjectTypeText      ObjectType = 100
+)
+
+/* Names of object types drawn without a sprite, sprite names are in spriteObjectTypeNames. */
+var objectTypeNames = map[ObjectType]string{
+	ObjectTypeCircleAoE: "CircleAoE",
+	ObjectTypeLineAoE:   "LineAoE",
+	ObjectTypeLine:      "Line",
+	ObjectTypeDonut:     "Donut",
+	ObjectTypeText:      "Text",
+}
+
+/* Broad grouping of object types. */
+type ObjectCategory int
+
+const (
+	CategoryOther ObjectCategory = iota
+	CategoryRoleMarker
+	CategoryWaymark
+	CategoryMarker
+	CategoryAoEShape
+	CategoryLine
+	CategoryText
+)
+
+var objectCategoryNames = map[ObjectCategory]string{
+	CategoryOther:      "other",
+	CategoryRoleMarker: "role_marker",
+	CategoryWaymark:    "waymark",
+	CategoryMarker:     "marker",
+	CategoryAoEShape:   "aoe_shape",
+	CategoryLine:       "line",
+	CategoryText:       "text",
+}
+
+/* Categories of object types drawn without a sprite. */
+var objectTypeCategories = map[ObjectType]ObjectCategory{
+	ObjectTypeCircleAoE: CategoryAoEShape,
+	ObjectTypeLineAoE:   CategoryAoEShape,
+	ObjectTypeLine:      CategoryLine,
+	ObjectTypeDonut:     CategoryAoEShape,
+	ObjectTypeText:      CategoryText,
+}
+
+/* Name of object type, as in the asset list for sprite objects. */
+func (t ObjectType) String() string {
+	if name := objectTypeLabel(t); name != "" {
+		return name
+	}
+	return fmt.Sprintf("ObjectType(%d)", int(t))
+}
+
+/* Category of object type. */
+func (t ObjectType) Category() ObjectCategory {
+	if category, ok := objectTypeCategories[t]; ok {
+		return category
+	}
+	if category, ok := spriteObjectTypeCategories[t]; ok {
+		return category
+	}
+	return CategoryOther
+}
+
+func (c ObjectCategory) String() string {
+	if name, ok := objectCategoryNames[c]; ok {
+		return name
+	}
+	return fmt.Sprintf("ObjectCategory(%d)", int(c))
+}
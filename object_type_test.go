@@ -0,0 +1,55 @@
+package strategy_board
+
+import "testing"
+
+func TestObjectTypeString(t *testing.T) {
+	tests := map[ObjectType]string{
+		ObjectTypeCircleAoE: "CircleAoE",
+		ObjectTypeText:      "Text",
+		-12345:              "ObjectType(-12345)",
+	}
+	for typeId, want := range tests {
+		if got := typeId.String(); got != want {
+			t.Errorf("%d: got %q, want %q", int(typeId), got, want)
+		}
+	}
+}
+
+func TestObjectTypeCategory(t *testing.T) {
+	tests := map[ObjectType]ObjectCategory{
+		ObjectTypeCircleAoE: CategoryAoEShape,
+		ObjectTypeLine:      CategoryLine,
+		ObjectTypeText:      CategoryText,
+		ObjectTypeTank:      CategoryRoleMarker,
+		-12345:              CategoryOther,
+	}
+	for typeId, want := range tests {
+		if got := typeId.Category(); got != want {
+			t.Errorf("%s: got %s, want %s", typeId, got, want)
+		}
+	}
+}
+
+/* Generated sprite object types match the embedded asset list, run go generate after rebuilding assets.zip. */
+func TestGeneratedObjectTypes(t *testing.T) {
+	assets, err := loadAssetList(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sprites := 0
+	for _, asset := range assets {
+		if _, ok := objectTypeNames[asset.ID]; ok {
+			continue
+		}
+		sprites++
+		if name := spriteObjectTypeNames[asset.ID]; name != asset.Name {
+			t.Errorf("%d: got %q, want %q from the asset list", int(asset.ID), name, asset.Name)
+		}
+		if _, ok := spriteObjectTypeCategories[asset.ID]; !ok {
+			t.Errorf("%d: no generated category", int(asset.ID))
+		}
+	}
+	if sprites != len(spriteObjectTypeNames) {
+		t.Errorf("got %d generated sprite object types, want %d from the asset list", len(spriteObjectTypeNames), sprites)
+	}
+}
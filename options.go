@@ -0,0 +1,93 @@
+package strategy_board
+
+import (
+	"image/color"
+	"math"
+)
+
+/* Limits applied while unpacking and parsing share codes, a limit of zero disables it. */
+type LoadOptions struct {
+	MaxShareCodeLength   int
+	MaxDecompressedBytes int
+	MaxObjects           int
+	MaxTextLength        int
+}
+
+/* Default limits, generous enough for any board the game produces. */
+var DefaultLoadOptions = LoadOptions{
+	MaxShareCodeLength:   16384,
+	MaxDecompressedBytes: 65535,
+	MaxObjects:           1024,
+	MaxTextLength:        1024,
+}
+
+/* Output size and quality of a drawn board. */
+type DrawOptions struct {
+	// Size of the output in pixels, when only one is set the other follows the board's 4:3 aspect ratio
+	// and when both are set the board is scaled to fit and centered.
+	Width  int
+	Height int
+	// Scale relative to the default 1024x768 canvas, used when neither width nor height is set.
+	Scale float64
+	// Draw at this many times the output size and scale down, for smoother edges at small sizes.
+	// Up to MaxSupersample, and the supersampled size can't be more than MaxDrawPixels.
+	Supersample int
+	// Draw hidden objects faded with a dashed outline instead of leaving them out.
+	ShowHidden bool
+	// Leave out the background image, the canvas is filled with background color instead.
+	HideBackground bool
+	// Color of the canvas behind the background image, transparent when unset.
+	BackgroundColor color.NRGBA
+}
+
+/* Default draw options, a 1024x768 image. */
+var DefaultDrawOptions = DrawOptions{
+	Scale:       1,
+	Supersample: 1,
+}
+
+/*
+Largest image drawn, in pixels after supersampling. Every pixel takes 4 bytes and drawing needs a few
+canvases this size, so the cap keeps a single draw to a few hundred megabytes.
+*/
+const MaxDrawPixels = 4096 * 4096
+
+/* Largest supersample factor, past it edges stop getting any smoother. */
+const MaxSupersample = 8
+
+/* Size of the output image and the scale and offset board pixels are drawn at, before supersampling. */
+func (o DrawOptions) layout() (width int, height int, scale float64, offsetX float64, offsetY float64, err error) {
+	if o.Width < 0 || o.Height < 0 || o.Scale < 0 || o.Supersample < 0 || o.Supersample > MaxSupersample {
+		return 0, 0, 0, 0, 0, DrawOptionsError
+	}
+	var w, h float64
+	switch {
+	case o.Width > 0 && o.Height > 0:
+		scale = min(float64(o.Width)/canvasWidth, float64(o.Height)/canvasHeight)
+		w, h = float64(o.Width), float64(o.Height)
+		offsetX, offsetY = (w-canvasWidth*scale)/2, (h-canvasHeight*scale)/2
+	case o.Width > 0:
+		scale = float64(o.Width) / canvasWidth
+		w, h = float64(o.Width), math.Round(canvasHeight*scale)
+	case o.Height > 0:
+		scale = float64(o.Height) / canvasHeight
+		w, h = math.Round(canvasWidth*scale), float64(o.Height)
+	default:
+		scale = o.Scale
+		if scale == 0 {
+			scale = 1
+		}
+		w, h = math.Round(canvasWidth*scale), math.Round(canvasHeight*scale)
+	}
+	// checked before converting to int, so huge sizes can't overflow
+	ss := float64(max(o.Supersample, 1))
+	if w < 1 || h < 1 || w*ss*h*ss > MaxDrawPixels {
+		return 0, 0, 0, 0, 0, DrawOptionsError
+	}
+	return int(w), int(h), scale, offsetX, offsetY, nil
+}
+
+/* Check value against limit, a limit of zero is never exceeded. */
+func exceedsLimit(value int, limit int) bool {
+	return limit > 0 && value > limit
+}
@@ -0,0 +1,40 @@
+package strategy_board
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestDrawOptionsLayout(t *testing.T) {
+	tests := []struct {
+		options       DrawOptions
+		width, height int
+	}{
+		{DefaultDrawOptions, 1024, 768},
+		{DrawOptions{Scale: 2, Supersample: 2}, 2048, 1536},
+		{DrawOptions{Width: 512}, 512, 384},
+		{DrawOptions{Width: 800, Height: 800}, 800, 800},
+	}
+	for _, test := range tests {
+		width, height, _, _, _, err := test.options.layout()
+		if err != nil || width != test.width || height != test.height {
+			t.Errorf("%+v: got %dx%d, %v, want %dx%d", test.options, width, height, err, test.width, test.height)
+		}
+	}
+
+	// sizes past the pixel cap, before or after supersampling, fail
+	for _, options := range []DrawOptions{
+		{Scale: 5},
+		{Scale: 2, Supersample: 4},
+		{Width: 1 << 20},
+		{Width: math.MaxInt, Height: math.MaxInt},
+		{Scale: math.MaxFloat64},
+		{Supersample: MaxSupersample + 1},
+		{Scale: -1},
+	} {
+		if _, _, _, _, _, err := options.layout(); !errors.Is(err, DrawOptionsError) {
+			t.Errorf("%+v: got %v, want draw options error", options, err)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package strategy_board
+
+/* Arc parameters of circle AoE and donut objects. */
+type ArcParams struct {
+	Angle       int `json:"angle"`
+	InnerRadius int `json:"inner_radius"`
+}
+
+/* Line AoE parameters, half of the rectangle's width and height in pixels. */
+type LineAoEParams struct {
+	HalfWidth  int `json:"half_width"`
+	HalfHeight int `json:"half_height"`
+}
+
+/* Line parameters, endpoints are in pixels on the default 1024x768 canvas. */
+type LineParams struct {
+	X1        float64 `json:"x1"`
+	Y1        float64 `json:"y1"`
+	X2        float64 `json:"x2"`
+	Y2        float64 `json:"y2"`
+	Thickness int     `json:"thickness"`
+}
+
+/* Check object is one of the given types and has at least count params. */
+func (o Object) checkParams(count int, typeIds ...ObjectType) error {
+	matches := false
+	for _, typeId := range typeIds {
+		if o.TypeID == typeId {
+			matches = true
+			break
+		}
+	}
+	if !matches {
+		return ParamsObjectTypeError
+	}
+	if len(o.Params) < count {
+		return MissingParamsError
+	}
+	return nil
+}
+
+/* Arc parameters of circle AoE and donut objects. */
+func (o Object) Arc() (ArcParams, error) {
+	if err := o.checkParams(2, ObjectTypeCircleAoE, ObjectTypeDonut); err != nil {
+		return ArcParams{}, err
+	}
+	return ArcParams{Angle: o.Params[0], InnerRadius: o.Params[1]}, nil
+}
+
+/* Parameters of line AoE objects. */
+func (o Object) LineAoE() (LineAoEParams, error) {
+	if err := o.checkParams(2, ObjectTypeLineAoE); err != nil {
+		return LineAoEParams{}, err
+	}
+	return LineAoEParams{HalfWidth: o.Params[0], HalfHeight: o.Params[1]}, nil
+}
+
+/* Parameters of line objects, the object's position is the first endpoint and params hold the second. */
+func (o Object) Line() (LineParams, error) {
+	if err := o.checkParams(3, ObjectTypeLine); err != nil {
+		return LineParams{}, err
+	}
+	x1, y1 := o.PixelPosition()
+	x2, y2 := NativeToCanvas(o.Params[0], o.Params[1], canvasWidth, canvasHeight)
+	return LineParams{X1: x1, Y1: y1, X2: x2, Y2: y2, Thickness: o.Params[2]}, nil
+}
@@ -5,6 +5,8 @@ import (
 	"compress/zlib"
 	"encoding/base64"
 	"encoding/binary"
+	"errors"
+	"hash/crc32"
 	"image/color"
 	"io"
 	"log"
@@ -20,7 +22,7 @@ const boardSuffix = "]"
 
 var forwardTranslationTable = map[rune]rune{'+': 'N', '-': 'P', '0': 'x', '1': 'g', '2': '0', '3': 'K', '4': '8', '5': 'S', '6': 'J', '7': '2', '8': 's', '9': 'Z', 'A': 'D', 'B': 'F', 'C': 't', 'D': 'T', 'E': '6', 'F': 'E', 'G': 'a', 'H': 'V', 'I': 'c', 'J': 'p', 'K': 'L', 'L': 'M', 'M': 'm', 'N': 'e', 'O': 'j', 'P': '9', 'Q': 'X', 'R': 'B', 'S': '4', 'T': 'R', 'U': 'Y', 'V': '7', 'W': '_', 'X': 'n', 'Y': 'O', 'Z': 'b', 'a': 'i', 'b': '-', 'c': 'v', 'd': 'H', 'e': 'C', 'f': 'A', 'g': 'r', 'h': 'W', 'i': 'o', 'j': 'd', 'k': 'I', 'l': 'q', 'm': 'h', 'n': 'U', 'o': 'l', 'p': 'k', 'q': '3', 'r': 'f', 's': 'y', 't': '5', 'u': 'G', 'v': 'w', 'w': '1', 'x': 'u', 'y': 'z', 'z': 'Q'}
 
-type BoardObjectFlag uint8
+type BoardObjectFlag uint16
 
 const (
 	Visible BoardObjectFlag = 1 << iota
@@ -29,11 +31,22 @@ const (
 	Locked
 )
 
+/* Flags that have a field on Object. */
+const knownObjectFlags = Visible | FlipHorizontal | FlipVertical | Locked
+
 /* Unpack board share code to raw bytes. */
 func Unpack(input string) ([]byte, error) {
+	return UnpackWithOptions(input, DefaultLoadOptions)
+}
+
+/* Unpack board share code to raw bytes, enforcing the given limits. */
+func UnpackWithOptions(input string, options LoadOptions) ([]byte, error) {
 	log.Println("Unpack strategy board")
+	if exceedsLimit(len(input), options.MaxShareCodeLength) {
+		return nil, &BoardParseError{Err: ShareCodeLengthLimitError, Offset: 0, Expected: options.MaxShareCodeLength, Actual: len(input)}
+	}
 	if !strings.HasPrefix(input, boardPrefix) || !strings.HasSuffix(input, boardSuffix) || len(input) < len(boardPrefix)+len(boardSuffix)+1 {
-		return nil, ParseError
+		return nil, &BoardParseError{Err: ParseError, Offset: 0}
 	}
 
 	input = input[len(boardPrefix) : len(input)-len(boardSuffix)]
@@ -49,78 +62,144 @@ func Unpack(input string) ([]byte, error) {
 		buffer[i] = mapOut(y)
 	}
 
+	// offsets of encoding errors are given in the share code, after the prefix and seed
 	base64Str, err := charmap.Windows1252.NewDecoder().String(string(buffer))
 	if err != nil {
-		return nil, err
+		return nil, &BoardParseError{Err: EncodingParseError, Offset: len(boardPrefix) + 1, Cause: err}
 	}
 
 	decoded, err := base64.RawURLEncoding.DecodeString(base64Str)
 	if err != nil {
-		return nil, err
+		offset := len(boardPrefix) + 1
+		var corrupt base64.CorruptInputError
+		if errors.As(err, &corrupt) {
+			offset += int(corrupt)
+		}
+		return nil, &BoardParseError{Err: EncodingParseError, Offset: offset, Cause: err}
+	}
+
+	// 6 byte header, crc32 checksum of compressed data followed by uncompressed length
+	if len(decoded) < 6 {
+		return nil, &BoardParseError{Err: UnexpectedEndParseError, Offset: 0, Expected: 6, Actual: len(decoded)}
+	}
+	checksum := binary.LittleEndian.Uint32(decoded)
+	length := int(binary.LittleEndian.Uint16(decoded[4:]))
+	if actualChecksum := crc32.ChecksumIEEE(decoded[6:]); actualChecksum != checksum {
+		return nil, &BoardParseError{Err: ChecksumParseError, Offset: 0, Expected: checksum, Actual: actualChecksum}
+	}
+	if exceedsLimit(length, options.MaxDecompressedBytes) {
+		return nil, &BoardParseError{Err: DecompressedSizeLimitError, Offset: 4, Expected: options.MaxDecompressedBytes, Actual: length}
 	}
 
 	z, err := zlib.NewReader(bytes.NewReader(decoded[6:]))
 	if err != nil {
-		return nil, err
+		return nil, &BoardParseError{Err: CompressionParseError, Offset: 6, Cause: err}
 	}
 
-	decompressed, err := io.ReadAll(z)
+	// read one byte past the limit so an oversized stream can be detected without inflating all of it
+	var zr io.Reader = z
+	if options.MaxDecompressedBytes > 0 {
+		zr = io.LimitReader(z, int64(options.MaxDecompressedBytes)+1)
+	}
+	decompressed, err := io.ReadAll(zr)
 	if err != nil {
-		return nil, err
+		return nil, &BoardParseError{Err: CompressionParseError, Offset: 6, Cause: err}
+	}
+	if exceedsLimit(len(decompressed), options.MaxDecompressedBytes) {
+		return nil, &BoardParseError{Err: DecompressedSizeLimitError, Offset: 6, Expected: options.MaxDecompressedBytes, Actual: len(decompressed)}
+	}
+	if len(decompressed) != length {
+		return nil, &BoardParseError{Err: LengthParseError, Offset: 4, Expected: length, Actual: len(decompressed)}
 	}
 
 	return decompressed, nil
 }
 
+/* Convert transparency percentage to alpha, values past 100 are treated as fully transparent. */
+func transparencyToAlpha(transparency byte) uint8 {
+	return uint8(math.Round(255.0 * (1.0 - float64(min(transparency, 100))/100.0)))
+}
+
 /* Parse strategy board data */
 func Parse(data []byte) (Board, error) {
+	return ParseWithOptions(data, DefaultLoadOptions)
+}
+
+/* Parse strategy board data, enforcing the given limits. */
+func ParseWithOptions(data []byte, options LoadOptions) (Board, error) {
 	log.Printf("Parse %d byte strategy board", len(data))
+	r := newReader(data)
 
-	// skip first 24 bytes
-	pos := 24
+	// keep first 24 bytes as is, their meaning is unknown
+	header, err := r.readBytes(headerLength)
+	if err != nil {
+		return Board{}, err
+	}
+	raw := &RawBoardData{Header: header, SectionHeaders: make(map[int][]byte)}
 
 	// assert section 1
-	if readUint16(data, &pos) != 1 {
-		return Board{}, SectionParseError
+	if err := r.readSectionNumber(1); err != nil {
+		return Board{}, err
 	}
 
 	// read board name
-	name := readString(data, &pos)
+	name, err := r.readString(options.MaxTextLength)
+	if err != nil {
+		return Board{}, err
+	}
 	log.Printf("  - NAME: %s", name)
 
 	// read objects and object text
 	objects := make([]Object, 0)
 	for {
+		r.section = 2
 		// object section is 2, if next uint16 isn't 2 then we're done parsing objects
-		if readUint16(data, &pos) != 2 {
-			pos -= 2
+		section, err := r.peekUint16()
+		if err != nil {
+			return Board{}, err
+		}
+		if section != 2 {
 			break
 		}
+		if exceedsLimit(len(objects)+1, options.MaxObjects) {
+			return Board{}, r.error(ObjectCountLimitError, r.pos, options.MaxObjects, len(objects)+1)
+		}
+		r.pos += 2
 		// read object type id
-		typeId := readUint16(data, &pos)
-		// read object text, type id 100 is text object
+		typeId, err := r.readUint16()
+		if err != nil {
+			return Board{}, err
+		}
+		// read object text
 		text := ""
-		if typeId == 100 {
+		if ObjectType(typeId) == ObjectTypeText {
 			// assert section 3
-			if readUint16(data, &pos) != 3 {
-				return Board{}, SectionParseError
+			if err := r.readSectionNumber(3); err != nil {
+				return Board{}, err
+			}
+			if text, err = r.readString(options.MaxTextLength); err != nil {
+				return Board{}, err
 			}
-			text = readString(data, &pos)
 		}
-		objects = append(objects, Object{TypeID: int(typeId), Text: text})
+		objects = append(objects, Object{TypeID: ObjectType(typeId), Text: text})
 	}
 	log.Printf("  - %d objects read", len(objects))
 
 	// read object flags
 	log.Println("  - Parse object flags")
-	if err := parseSectionHeader(4, data, &pos, objects); err != nil {
+	if err := parseSectionHeader(4, r, objects, raw); err != nil {
 		return Board{}, err
 	}
 	for i := range objects {
-		flags := BoardObjectFlag(readUint16(data, &pos))
-		objects[i].Visible = Visible&flags != 0
-		objects[i].FlipHorizontal = FlipHorizontal&flags != 0
-		objects[i].FlipVertical = FlipVertical&flags != 0
+		flags, err := r.readUint16()
+		if err != nil {
+			return Board{}, err
+		}
+		objects[i].Visible = Visible&BoardObjectFlag(flags) != 0
+		objects[i].FlipHorizontal = FlipHorizontal&BoardObjectFlag(flags) != 0
+		objects[i].FlipVertical = FlipVertical&BoardObjectFlag(flags) != 0
+		objects[i].Locked = Locked&BoardObjectFlag(flags) != 0
+		objects[i].Flags = BoardObjectFlag(flags)
 		if logVerbose {
 			log.Printf("    - OBJ %d Flags: %d", i+1, flags)
 		}
@@ -128,12 +207,20 @@ func Parse(data []byte) (Board, error) {
 
 	// read object coordinates
 	log.Println("  - Parse object coordinates")
-	if err := parseSectionHeader(5, data, &pos, objects); err != nil {
+	if err := parseSectionHeader(5, r, objects, raw); err != nil {
 		return Board{}, err
 	}
 	for i := range objects {
-		objects[i].X = int(math.Round((float64(readUint16(data, &pos)) / 5120) * 1024))
-		objects[i].Y = int(math.Round((float64(readUint16(data, &pos)) / 3840) * 768))
+		x, err := r.readUint16()
+		if err != nil {
+			return Board{}, err
+		}
+		y, err := r.readUint16()
+		if err != nil {
+			return Board{}, err
+		}
+		objects[i].X = x
+		objects[i].Y = y
 		if logVerbose {
 			log.Printf("    - OBJ %d Coordinates: %d, %d", i+1, objects[i].X, objects[i].Y)
 		}
@@ -141,11 +228,13 @@ func Parse(data []byte) (Board, error) {
 
 	// read object angle
 	log.Println("  - Parse object angles")
-	if err := parseSectionHeader(6, data, &pos, objects); err != nil {
+	if err := parseSectionHeader(6, r, objects, raw); err != nil {
 		return Board{}, err
 	}
 	for i := range objects {
-		objects[i].Angle = readInt16(data, &pos)
+		if objects[i].Angle, err = r.readInt16(); err != nil {
+			return Board{}, err
+		}
 		if logVerbose {
 			log.Printf("    - OBJ %d Angle: %d", i+1, objects[i].Angle)
 		}
@@ -153,27 +242,37 @@ func Parse(data []byte) (Board, error) {
 
 	// read object scale
 	log.Println("  - Parse object scales")
-	if err := parseSectionHeader(7, data, &pos, objects); err != nil {
+	if err := parseSectionHeader(7, r, objects, raw); err != nil {
 		return Board{}, err
 	}
 	for i := range objects {
-		objects[i].Scale = int(readByte(data, &pos))
+		scale, err := r.readByte()
+		if err != nil {
+			return Board{}, err
+		}
+		objects[i].Scale = int(scale)
 		if logVerbose {
 			log.Printf("    - OBJ %d Scale: %d", i+1, objects[i].Scale)
 		}
 	}
-	pos += len(objects) % 2
+	if raw.ScalePadding, err = r.readBytes(len(objects) % 2); err != nil {
+		return Board{}, err
+	}
 
 	// read object color
-	if err := parseSectionHeader(8, data, &pos, objects); err != nil {
+	if err := parseSectionHeader(8, r, objects, raw); err != nil {
 		return Board{}, err
 	}
 	for i := range objects {
+		rgbt, err := r.readBytes(4)
+		if err != nil {
+			return Board{}, err
+		}
 		objects[i].Color = color.NRGBA{
-			uint8(readByte(data, &pos)),
-			uint8(readByte(data, &pos)),
-			uint8(readByte(data, &pos)),
-			uint8(math.Round(255.0 * (1.0 - float64(uint8(readByte(data, &pos)))/100.0))),
+			rgbt[0],
+			rgbt[1],
+			rgbt[2],
+			transparencyToAlpha(rgbt[3]),
 		}
 		if logVerbose {
 			log.Printf("    - OBJ %d Color: R%d G%d B%d A%d", i+1, objects[i].Color.R, objects[i].Color.G, objects[i].Color.B, objects[i].Color.A)
@@ -182,12 +281,15 @@ func Parse(data []byte) (Board, error) {
 
 	// read object params
 	for _, section := range []int{10, 11, 12} {
-		if err := parseSectionHeader(section, data, &pos, objects); err != nil {
+		if err := parseSectionHeader(section, r, objects, raw); err != nil {
 			return Board{}, err
 		}
 		for i := range objects {
-			objects[i].Params = append(objects[i].Params, readInt16(data, &pos))
-
+			param, err := r.readInt16()
+			if err != nil {
+				return Board{}, err
+			}
+			objects[i].Params = append(objects[i].Params, param)
 		}
 	}
 	if logVerbose {
@@ -196,32 +298,55 @@ func Parse(data []byte) (Board, error) {
 		}
 	}
 
-	if readUint16(data, &pos) != 3 {
-		return Board{}, SectionParseError
+	// read background
+	if err := r.readSectionNumber(3); err != nil {
+		return Board{}, err
 	}
-	pos += 4
-	background := readUint16(data, &pos)
+	if raw.BackgroundHeader, err = r.readBytes(4); err != nil {
+		return Board{}, err
+	}
+	background, err := r.readUint16()
+	if err != nil {
+		return Board{}, err
+	}
+
+	// keep any trailing data
+	raw.Trailer, _ = r.readBytes(r.remaining())
 
-	return Board{Name: name, Background: background, Objects: objects}, nil
+	return Board{Name: name, Background: background, Objects: objects, Raw: raw}, nil
 
 }
 
 func Load(input string) (Board, error) {
+	return LoadWithOptions(input, DefaultLoadOptions)
+}
+
+/* Unpack and parse board share code, enforcing the given limits. */
+func LoadWithOptions(input string, options LoadOptions) (Board, error) {
 	log.Println("Load strategy board")
-	data, err := Unpack(input)
+	data, err := UnpackWithOptions(input, options)
 	if err != nil {
 		return Board{}, err
 	}
-	return Parse(data)
+	return ParseWithOptions(data, options)
 }
 
-func parseSectionHeader(expectedSectionNumber int, data []byte, pos *int, objects []Object) error {
-	if readUint16(data, pos) != expectedSectionNumber {
-		return SectionParseError
+func parseSectionHeader(expectedSectionNumber int, r *reader, objects []Object, raw *RawBoardData) error {
+	if err := r.readSectionNumber(expectedSectionNumber); err != nil {
+		return err
+	}
+	sectionHeader, err := r.readBytes(2)
+	if err != nil {
+		return err
+	}
+	raw.SectionHeaders[expectedSectionNumber] = sectionHeader
+	offset := r.pos
+	objectCount, err := r.readUint16()
+	if err != nil {
+		return err
 	}
-	*pos += 2
-	if readUint16(data, pos) != len(objects) {
-		return ObjectCountParseError
+	if objectCount != len(objects) {
+		return r.error(ObjectCountParseError, offset, len(objects), objectCount)
 	}
 	return nil
 }
@@ -271,27 +396,3 @@ func mapOut(n int) rune {
 	}
 	return '_'
 }
-
-func readByte(data []byte, pos *int) byte {
-	out := data[*pos]
-	*pos += 1
-	return out
-}
-
-func readUint16(data []byte, pos *int) int {
-	out := binary.LittleEndian.Uint16(data[*pos:])
-	*pos += 2
-	return int(out)
-}
-
-func readInt16(data []byte, pos *int) int {
-	return int(int16(readUint16(data, pos)))
-}
-
-func readString(data []byte, pos *int) string {
-	length := int(binary.LittleEndian.Uint16(data[*pos:]))
-	*pos += 2
-	out := string(data[*pos : *pos+length])
-	*pos += length
-	return out
-}
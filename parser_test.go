@@ -0,0 +1,186 @@
+package strategy_board
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"os"
+	"testing"
+)
+
+/* Share codes copied from the game, the only ones that show Unpack and Serialize agree with the game. */
+const gameShareCodesPath = "testdata/game_share_codes.txt"
+
+/* Share codes in testdata, made by Encode and copied from the game, used to seed fuzz tests. */
+func testShareCodes(t testing.TB) []string {
+	return append(readShareCodes(t, "testdata/share_codes.txt"), readShareCodes(t, gameShareCodesPath)...)
+}
+
+func readShareCodes(t testing.TB, path string) []string {
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	found, err := FindShareCodes(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	codes := make([]string, 0, len(found))
+	for _, code := range found {
+		codes = append(codes, code.Code)
+	}
+	return codes
+}
+
+/* Fail unless err is nil or a BoardParseError. */
+func checkParseError(t *testing.T, err error) {
+	var parseErr *BoardParseError
+	if err != nil && !errors.As(err, &parseErr) {
+		t.Errorf("expected *BoardParseError, got %T: %s", err, err)
+	}
+}
+
+func FuzzUnpack(f *testing.F) {
+	for _, code := range testShareCodes(f) {
+		f.Add(code)
+		f.Add(code[:len(code)/2] + "]")
+	}
+	f.Add("")
+	f.Add("[stgy:a]")
+	f.Add("[stgy:aA]")
+	f.Fuzz(func(t *testing.T, input string) {
+		data, err := Unpack(input)
+		checkParseError(t, err)
+		if err == nil {
+			_, err = Parse(data)
+			checkParseError(t, err)
+		}
+	})
+}
+
+func FuzzParse(f *testing.F) {
+	for _, code := range testShareCodes(f) {
+		data, err := Unpack(code)
+		if err != nil {
+			f.Fatalf("unpack %s: %s", code, err)
+		}
+		f.Add(data)
+		f.Add(data[:len(data)/2])
+	}
+	f.Add([]byte{})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		_, err := Parse(data)
+		checkParseError(t, err)
+	})
+}
+
+func TestGameShareCodes(t *testing.T) {
+	codes := readShareCodes(t, gameShareCodesPath)
+	if len(codes) == 0 {
+		t.Skipf("no share codes copied from the game in %s, the header checksum and lossless re-encoding are only checked against Encode", gameShareCodesPath)
+	}
+	for _, code := range codes {
+		data, err := Unpack(code)
+		if err != nil {
+			t.Errorf("unpack %s: %s", code, err)
+			continue
+		}
+		board, err := Parse(data)
+		if err != nil {
+			t.Errorf("parse %s: %s", code, err)
+			continue
+		}
+		again, err := Serialize(board)
+		if err != nil {
+			t.Errorf("serialize %s: %s", code, err)
+			continue
+		}
+		if !bytes.Equal(data, again) {
+			t.Errorf("%s changed after round trip:\n%x\n%x", code, data, again)
+		}
+	}
+}
+
+/* Share code with a header made from the given checksum and length rather than the data. */
+func testShareCodeWithHeader(t *testing.T, data []byte, checksum func(compressed []byte) uint32, length int) string {
+	var compressed bytes.Buffer
+	z := zlib.NewWriter(&compressed)
+	z.Write(data)
+	if err := z.Close(); err != nil {
+		t.Fatal(err)
+	}
+	decoded := binary.LittleEndian.AppendUint32(nil, checksum(compressed.Bytes()))
+	decoded = binary.LittleEndian.AppendUint16(decoded, uint16(length))
+	return scramble(append(decoded, compressed.Bytes()...), defaultSeed)
+}
+
+func TestUnpackHeader(t *testing.T) {
+	data, err := Serialize(testBoards()[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		checksum func(compressed []byte) uint32
+		length   int
+		want     error
+	}{
+		{"valid", crc32.ChecksumIEEE, len(data), nil},
+		{"wrong checksum", func(compressed []byte) uint32 { return crc32.ChecksumIEEE(compressed) ^ 1 }, len(data), ChecksumParseError},
+		{"checksum of uncompressed data", func([]byte) uint32 { return crc32.ChecksumIEEE(data) }, len(data), ChecksumParseError},
+		{"short length", crc32.ChecksumIEEE, len(data) - 1, LengthParseError},
+		{"long length", crc32.ChecksumIEEE, len(data) + 1, LengthParseError},
+	}
+	for _, test := range tests {
+		got, err := Unpack(testShareCodeWithHeader(t, data, test.checksum, test.length))
+		if test.want == nil {
+			if err != nil || !bytes.Equal(got, data) {
+				t.Errorf("%s: got %v, want the board data", test.name, err)
+			}
+			continue
+		}
+		var parseErr *BoardParseError
+		if !errors.Is(err, test.want) || !errors.As(err, &parseErr) {
+			t.Errorf("%s: got %v, want %v", test.name, err, test.want)
+		}
+		if errors.Is(err, ChecksumParseError) && errors.Is(err, LengthParseError) {
+			t.Errorf("%s: got both checksum and length errors, want one", test.name)
+		}
+	}
+}
+
+func TestLoadOptionsLimits(t *testing.T) {
+	board := testBoards()[1]
+	code, err := Encode(board)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := Serialize(board)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		options LoadOptions
+		want    error
+	}{
+		{"share code length", LoadOptions{MaxShareCodeLength: len(code) - 1}, ShareCodeLengthLimitError},
+		{"decompressed size", LoadOptions{MaxDecompressedBytes: len(data) - 1}, DecompressedSizeLimitError},
+		{"object count", LoadOptions{MaxObjects: len(board.Objects) - 1}, ObjectCountLimitError},
+		{"text length", LoadOptions{MaxTextLength: len(board.Name) - 1}, TextLengthLimitError},
+	}
+	for _, test := range tests {
+		if _, err := LoadWithOptions(code, test.options); !errors.Is(err, test.want) {
+			t.Errorf("%s: got %v, want %v", test.name, err, test.want)
+		}
+	}
+	// limits exactly at the board's size pass, the longest string on the board is its text object
+	longest := max(len(board.Name), len(board.Objects[0].Text))
+	exact := LoadOptions{MaxShareCodeLength: len(code), MaxDecompressedBytes: len(data), MaxObjects: len(board.Objects), MaxTextLength: longest}
+	if _, err := LoadWithOptions(code, exact); err != nil {
+		t.Errorf("limits equal to the board's size: got %v, want no error", err)
+	}
+}
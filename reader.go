@@ -0,0 +1,94 @@
+package strategy_board
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+/* Bounds checked cursor over raw board data. */
+type reader struct {
+	data    []byte
+	pos     int
+	section int
+}
+
+func newReader(data []byte) *reader {
+	return &reader{data: data}
+}
+
+/* Build a parse error at the given offset of the current section. */
+func (r *reader) error(err error, offset int, expected any, actual any) error {
+	return &BoardParseError{Err: err, Section: r.section, Offset: offset, Expected: expected, Actual: actual}
+}
+
+/* Number of bytes left to read. */
+func (r *reader) remaining() int {
+	return len(r.data) - r.pos
+}
+
+func (r *reader) readBytes(length int) ([]byte, error) {
+	if length < 0 || length > r.remaining() {
+		return nil, r.error(UnexpectedEndParseError, r.pos, length, r.remaining())
+	}
+	out := bytes.Clone(r.data[r.pos : r.pos+length])
+	r.pos += length
+	return out, nil
+}
+
+func (r *reader) readByte() (byte, error) {
+	if r.remaining() < 1 {
+		return 0, r.error(UnexpectedEndParseError, r.pos, 1, r.remaining())
+	}
+	out := r.data[r.pos]
+	r.pos += 1
+	return out, nil
+}
+
+func (r *reader) peekUint16() (int, error) {
+	if r.remaining() < 2 {
+		return 0, r.error(UnexpectedEndParseError, r.pos, 2, r.remaining())
+	}
+	return int(binary.LittleEndian.Uint16(r.data[r.pos:])), nil
+}
+
+func (r *reader) readUint16() (int, error) {
+	out, err := r.peekUint16()
+	if err != nil {
+		return 0, err
+	}
+	r.pos += 2
+	return out, nil
+}
+
+func (r *reader) readInt16() (int, error) {
+	out, err := r.readUint16()
+	return int(int16(out)), err
+}
+
+/* Read a length prefixed string, a maxLength of zero is unlimited. */
+func (r *reader) readString(maxLength int) (string, error) {
+	offset := r.pos
+	length, err := r.readUint16()
+	if err != nil {
+		return "", err
+	}
+	if exceedsLimit(length, maxLength) {
+		return "", r.error(TextLengthLimitError, offset, maxLength, length)
+	}
+	out, err := r.readBytes(length)
+	return string(out), err
+}
+
+/* Read a section number, assert it matches the expected one and enter that section. */
+func (r *reader) readSectionNumber(expectedSectionNumber int) error {
+	r.section = expectedSectionNumber
+	offset := r.pos
+	sectionNumber, err := r.readUint16()
+	if err != nil {
+		return err
+	}
+	if sectionNumber != expectedSectionNumber {
+		return r.error(SectionParseError, offset, expectedSectionNumber, sectionNumber)
+	}
+	return nil
+}
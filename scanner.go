@@ -0,0 +1,117 @@
+package strategy_board
+
+import (
+	"io"
+	"strings"
+	"unicode"
+)
+
+/* Share code found in text. */
+type ShareCode struct {
+	Code   string `json:"code"`
+	Offset int    `json:"offset"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+}
+
+/* Rune of the scanned text along with where it was found. */
+type scannedRune struct {
+	r      rune
+	offset int
+	line   int
+	column int
+}
+
+/* Characters that are invisible and commonly sneak into pasted text. */
+func isZeroWidth(r rune) bool {
+	switch r {
+	case '\u200b', '\u200c', '\u200d', '\u2060', '\ufeff', '\u00ad':
+		return true
+	}
+	return false
+}
+
+/* Characters allowed in the body of a share code. */
+func isShareCodeRune(r rune) bool {
+	return (r >= 'A' && r <= 'Z') || (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '+' || r == '-' || r == '_'
+}
+
+/*
+Find every share code in text, such as a chat log or a pasted document.
+Line breaks inside a code, along with whitespace around them, and zero-width characters are dropped from
+the returned code. Any other whitespace ends the code, so text after an unclosed code isn't taken as part of it.
+*/
+func FindShareCodes(r io.Reader) ([]ShareCode, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// collect runes with their position, skipping zero-width characters
+	runes := make([]scannedRune, 0, len(data))
+	line, column := 1, 1
+	for offset, c := range string(data) {
+		if !isZeroWidth(c) {
+			runes = append(runes, scannedRune{c, offset, line, column})
+		}
+		column++
+		if c == '\n' {
+			line++
+			column = 1
+		}
+	}
+
+	prefix := []rune(boardPrefix)
+	out := make([]ShareCode, 0)
+	for i := 0; i < len(runes); i++ {
+		if !hasScannedPrefix(runes[i:], prefix) {
+			continue
+		}
+		var code strings.Builder
+		code.WriteString(boardPrefix)
+		end := -1
+		for j := i + len(prefix); j < len(runes); j++ {
+			c := runes[j].r
+			if string(c) == boardSuffix {
+				end = j
+				break
+			}
+			if unicode.IsSpace(c) {
+				// only whitespace around a line break, from wrapping, can be inside a code
+				k, wrapped := j, false
+				for ; k < len(runes) && unicode.IsSpace(runes[k].r); k++ {
+					wrapped = wrapped || runes[k].r == '\n' || runes[k].r == '\r'
+				}
+				if !wrapped {
+					break
+				}
+				j = k - 1
+				continue
+			}
+			if !isShareCodeRune(c) {
+				break
+			}
+			code.WriteRune(c)
+		}
+		// need a closing bracket and at least one character of code
+		if end < 0 || code.Len() == len(boardPrefix) {
+			continue
+		}
+		code.WriteString(boardSuffix)
+		out = append(out, ShareCode{Code: code.String(), Offset: runes[i].offset, Line: runes[i].line, Column: runes[i].column})
+		i = end
+	}
+	return out, nil
+}
+
+func hasScannedPrefix(runes []scannedRune, prefix []rune) bool {
+	if len(runes) < len(prefix) {
+		return false
+	}
+	for i, c := range prefix {
+		if runes[i].r != c {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,58 @@
+package strategy_board
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFindShareCodes(t *testing.T) {
+	const code = "[stgy:aBCDEF]"
+	tests := []struct {
+		name  string
+		input string
+		want  []ShareCode
+	}{
+		{"code alone", code, []ShareCode{{code, 0, 1, 1}}},
+		{"code in a message", "stack here: " + code + " thanks", []ShareCode{{code, 12, 1, 13}}},
+		{
+			"several codes",
+			"first " + code + "\nsecond [stgy:aXYZ]",
+			[]ShareCode{{code, 6, 1, 7}, {"[stgy:aXYZ]", 27, 2, 8}},
+		},
+		{"wrapped code", "[stgy:aBC\nDEF]", []ShareCode{{code, 0, 1, 1}}},
+		{"wrapped code with indent", "[stgy:aBC \r\n   DEF]", []ShareCode{{code, 0, 1, 1}}},
+		{"zero width characters", "\u200b[stgy:\u200baBC\u2060DEF\ufeff]", []ShareCode{{code, 3, 1, 2}}},
+		{"multi-byte text before", "ボード " + code, []ShareCode{{code, 10, 1, 5}}},
+		{"unclosed code before text", "[stgy:aABC and more text]", []ShareCode{}},
+		{"unclosed code before a code", "[stgy:aABC " + code, []ShareCode{{code, 11, 1, 12}}},
+		{"code broken by punctuation", "[stgy:aABC.DEF]", []ShareCode{}},
+		{"empty code", "[stgy:a]", []ShareCode{}},
+		{"no code", "anyone up for savage tonight?", []ShareCode{}},
+	}
+	for _, test := range tests {
+		got, err := FindShareCodes(strings.NewReader(test.input))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got %+v, want %+v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestFindShareCodesLoad(t *testing.T) {
+	// a real code wrapped the way chat clients wrap it still loads
+	code := readShareCodes(t, "testdata/share_codes.txt")[1]
+	wrapped := "plan for tonight:\n" + code[:40] + "\n" + code[40:80] + "\r\n  " + code[80:] + "\nsee you there"
+	found, err := FindShareCodes(strings.NewReader(wrapped))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 1 || found[0].Code != code || found[0].Line != 2 {
+		t.Fatalf("got %+v, want %s on line 2", found, code)
+	}
+	if _, err := Load(found[0].Code); err != nil {
+		t.Error(err)
+	}
+}
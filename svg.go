@@ -0,0 +1,253 @@
+package strategy_board
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"log"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/fogleman/gg"
+)
+
+/* SVG document being written, sprites are embedded once per image and alpha. */
+type svgDocument struct {
+	out      bytes.Buffer
+	clipIDs  int
+	images   map[svgImageKey]string
+	assets   []Asset
+	arcImage image.Image
+}
+
+type svgImageKey struct {
+	id    ObjectType
+	alpha uint8
+}
+
+/* Draw board as an SVG document the size of the default 1024x768 canvas. */
+func DrawSVG(board Board, w io.Writer) error {
+	assetList, err := board.Assets()
+	if err != nil {
+		return err
+	}
+	arcImage, err := loadArcImage(nil)
+	if err != nil {
+		return err
+	}
+	return drawSVG(board, assetList, arcImage, w)
+}
+
+/* Draw board as an SVG document with the given assets, as returned by Board.Assets, and circle AoE texture. */
+func drawSVG(board Board, assets []Asset, arcImage image.Image, w io.Writer) error {
+	log.Println("Draw strategy board as SVG")
+
+	d := &svgDocument{images: make(map[svgImageKey]string), assets: assets, arcImage: arcImage}
+	fmt.Fprintf(&d.out, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n", canvasWidth, canvasHeight, canvasWidth, canvasHeight)
+
+	// draw background
+	for _, asset := range assets {
+		if asset.ID == -1 {
+			log.Printf("  - Draw background (ID=%d)", board.Background)
+			uri, err := d.imageURI(asset.ID, asset.Image, 255)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(&d.out, `<image width="%d" height="%d" href="%s"/>`+"\n", canvasWidth, canvasHeight, uri)
+			break
+		}
+	}
+
+	// draw each board object
+	for _, object := range slices.Backward(board.Objects) {
+		if err := d.drawObject(object); err != nil {
+			return err
+		}
+	}
+
+	d.out.WriteString("</svg>\n")
+	_, err := w.Write(d.out.Bytes())
+	return err
+}
+
+func (d *svgDocument) drawObject(object Object) error {
+	log.Printf("  - Draw object (TYPE=%d)", object.TypeID)
+	if !object.Visible {
+		log.Println("    - Object not visible, skipping")
+		return nil
+	}
+	switch object.TypeID {
+	case ObjectTypeCircleAoE:
+		return d.drawArc(object, d.arcImage)
+	case ObjectTypeLineAoE:
+		return d.drawLineAoe(object)
+	case ObjectTypeLine:
+		return d.drawLine(object)
+	case ObjectTypeDonut:
+		return d.drawArc(object, nil)
+	case ObjectTypeText:
+		return d.drawTextObject(object)
+	default:
+		for _, a := range d.assets {
+			if a.ID == object.TypeID {
+				return d.drawImageObject(object, &a)
+			}
+		}
+	}
+	log.Printf("Asset not found: %d", object.TypeID)
+	return AssetNotFound
+}
+
+/* Image as a PNG data URI, faded the way drawImageObject fades it. */
+func (d *svgDocument) imageURI(id ObjectType, src image.Image, alpha uint8) (string, error) {
+	key := svgImageKey{id, alpha}
+	if uri, ok := d.images[key]; ok {
+		return uri, nil
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, fadeImage(src, alpha)); err != nil {
+		return "", err
+	}
+	uri := "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes())
+	d.images[key] = uri
+	return uri, nil
+}
+
+func (d *svgDocument) drawImageObject(object Object, asset *Asset) error {
+	uri, err := d.imageURI(asset.ID, asset.Image, object.Color.A)
+	if err != nil {
+		return err
+	}
+	m := gg.Identity().Translate(object.PixelPosition()).Scale(object.ScaleFactor(asset.Scale)).Rotate(gg.Radians(float64(object.Angle)))
+	size := asset.Image.Bounds().Size()
+	fmt.Fprintf(&d.out, `<image x="%s" y="%s" width="%d" height="%d" transform="%s" href="%s"/>`+"\n",
+		svgNumber(-float64(size.X)/2), svgNumber(-float64(size.Y)/2), size.X, size.Y, svgMatrix(m), uri)
+	return nil
+}
+
+func (d *svgDocument) drawLineAoe(object Object) error {
+	params, err := object.LineAoE()
+	if err != nil {
+		return err
+	}
+	m := gg.Identity().Translate(object.PixelPosition()).Rotate(gg.Radians(float64(object.Angle)))
+	w, h := float64(params.HalfWidth), float64(params.HalfHeight)
+	fmt.Fprintf(&d.out, `<rect x="%s" y="%s" width="%s" height="%s" transform="%s" %s/>`+"\n",
+		svgNumber(-w), svgNumber(-h), svgNumber(w*2), svgNumber(h*2), svgMatrix(m), svgPaint("fill", object.Color))
+	return nil
+}
+
+func (d *svgDocument) drawLine(object Object) error {
+	params, err := object.Line()
+	if err != nil {
+		return err
+	}
+	thickness := float64(params.Thickness)
+	fmt.Fprintf(&d.out, `<line x1="%s" y1="%s" x2="%s" y2="%s" stroke-width="%s" stroke-linecap="round" %s/>`+"\n",
+		svgNumber(params.X1), svgNumber(params.Y1), svgNumber(params.X2), svgNumber(params.Y2), svgNumber(thickness*2), svgPaint("stroke", object.Color))
+	dot := svgPaint("fill", color.NRGBA{255, 255, 255, object.Color.A})
+	for _, p := range [][2]float64{{params.X1, params.Y1}, {params.X2, params.Y2}} {
+		fmt.Fprintf(&d.out, `<circle cx="%s" cy="%s" r="%s" %s/>`+"\n", svgNumber(p[0]), svgNumber(p[1]), svgNumber(thickness), dot)
+	}
+	return nil
+}
+
+func (d *svgDocument) drawArc(object Object, image image.Image) error {
+	shape, err := object.arcShape()
+	if err != nil {
+		return err
+	}
+
+	// outline of the arc and its inner circle, in arc coordinates
+	var path strings.Builder
+	fmt.Fprintf(&path, "M%s %s", svgNumber(shape.outerRadius*math.Cos(shape.startAngle)), svgNumber(shape.outerRadius*math.Sin(shape.startAngle)))
+	svgArcTo(&path, shape.outerRadius, shape.startAngle, shape.endAngle)
+	fmt.Fprintf(&path, "L%s %s", svgNumber(shape.innerRadius*math.Cos(shape.endAngle)), svgNumber(shape.innerRadius*math.Sin(shape.endAngle)))
+	svgArcTo(&path, shape.innerRadius, shape.endAngle, shape.startAngle)
+	path.WriteString("Z")
+
+	transform := svgMatrix(object.arcMatrix(shape))
+	if image == nil {
+		fmt.Fprintf(&d.out, `<path d="%s" transform="%s" %s/>`+"\n", path.String(), transform, svgPaint("fill", color.NRGBA{254, 161, 49, object.Color.A}))
+		return nil
+	}
+
+	// textured arc, the image is stretched over the full circle and clipped to the arc
+	uri, err := d.imageURI(ObjectTypeCircleAoE, image, object.Color.A)
+	if err != nil {
+		return err
+	}
+	d.clipIDs++
+	clipID := fmt.Sprintf("arc%d", d.clipIDs)
+	r := svgNumber(shape.outerRadius)
+	fmt.Fprintf(&d.out, `<g transform="%s"><clipPath id="%s"><path d="%s"/></clipPath>`, transform, clipID, path.String())
+	fmt.Fprintf(&d.out, `<image x="-%s" y="-%s" width="%s" height="%s" preserveAspectRatio="none" clip-path="url(#%s)" href="%s"/></g>`+"\n",
+		r, r, svgNumber(shape.outerRadius*2), svgNumber(shape.outerRadius*2), clipID, uri)
+	return nil
+}
+
+/* Add arc around the origin to path, split in half turns since an SVG arc can't draw a full circle. */
+func svgArcTo(path *strings.Builder, radius float64, from float64, to float64) {
+	if radius == 0 {
+		return
+	}
+	steps := max(1, int(math.Ceil(math.Abs(to-from)/math.Pi)))
+	sweep := 1
+	if to < from {
+		sweep = 0
+	}
+	for i := 1; i <= steps; i++ {
+		angle := from + (to-from)*float64(i)/float64(steps)
+		fmt.Fprintf(path, "A%s %s 0 0 %d %s %s", svgNumber(radius), svgNumber(radius), sweep, svgNumber(radius*math.Cos(angle)), svgNumber(radius*math.Sin(angle)))
+	}
+}
+
+func (d *svgDocument) drawTextObject(object Object) error {
+	if object.Text == "" {
+		return nil
+	}
+	fontFace, err := loadFont(nil)
+	if err != nil {
+		return err
+	}
+	// place the baseline where drawTextObject's centered anchor puts it
+	x, y := object.PixelPosition()
+	y += float64(fontFace.Metrics().Height) / 64 / 2
+	var text bytes.Buffer
+	if err := xml.EscapeText(&text, []byte(object.Text)); err != nil {
+		return err
+	}
+	style := fmt.Sprintf(`font-family="Roboto, sans-serif" font-weight="500" font-size="%d" text-anchor="middle"`, assetFontSize)
+	fmt.Fprintf(&d.out, `<text x="%s" y="%s" %s %s>%s</text>`+"\n", svgNumber(x), svgNumber(y), style, svgPaint("fill", color.NRGBA{0, 0, 0, object.Color.A}), text.String())
+	fmt.Fprintf(&d.out, `<text x="%s" y="%s" %s %s>%s</text>`+"\n", svgNumber(x-2), svgNumber(y-2), style, svgPaint("fill", object.Color), text.String())
+	return nil
+}
+
+/* Fill or stroke attributes for a color, with its alpha as opacity. */
+func svgPaint(attribute string, c color.NRGBA) string {
+	paint := fmt.Sprintf(`%s="#%02x%02x%02x"`, attribute, c.R, c.G, c.B)
+	if c.A != 255 {
+		paint += fmt.Sprintf(` %s-opacity="%s"`, attribute, svgNumber(float64(c.A)/255))
+	}
+	return paint
+}
+
+func svgMatrix(m gg.Matrix) string {
+	return fmt.Sprintf("matrix(%s %s %s %s %s %s)", svgNumber(m.XX), svgNumber(m.YX), svgNumber(m.XY), svgNumber(m.YY), svgNumber(m.X0), svgNumber(m.Y0))
+}
+
+/* Number rounded to a thousandth, without trailing zeros. */
+func svgNumber(value float64) string {
+	value = math.Round(value*1000) / 1000
+	if value == 0 {
+		value = 0 // no negative zero
+	}
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
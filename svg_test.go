@@ -0,0 +1,66 @@
+package strategy_board
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDrawSVGReferenceRender(t *testing.T) {
+	sprite := testSprite()
+	background := image.NewNRGBA(image.Rect(0, 0, 4, 3))
+	for i := range background.Pix {
+		background.Pix[i] = uint8(i * 5)
+	}
+	assets := []Asset{sprite, {Name: "Background", ID: -1, Image: background}}
+
+	board := Board{Name: "SVG", Background: 1, Objects: []Object{
+		{TypeID: ObjectTypeText, Text: "Stack <here> & there", Visible: true, X: 2560, Y: 500, Scale: 100, Color: color.NRGBA{255, 255, 255, 255}, Params: []int{0, 0, 0}},
+		{TypeID: sprite.ID, Visible: true, X: 4000, Y: 600, Angle: 30, Scale: 150, Color: color.NRGBA{255, 255, 255, 128}, Params: []int{0, 0, 0}},
+		{TypeID: sprite.ID, Visible: true, FlipHorizontal: true, X: 4500, Y: 600, Scale: 100, Color: color.NRGBA{255, 255, 255, 255}, Params: []int{0, 0, 0}},
+		{TypeID: ObjectTypeCircleAoE, Visible: true, X: 1500, Y: 1500, Angle: 30, Scale: 50, Color: color.NRGBA{255, 255, 255, 200}, Params: []int{90, 0, 0}},
+		{TypeID: ObjectTypeCircleAoE, Visible: true, X: 1000, Y: 3000, Scale: 40, Color: color.NRGBA{255, 255, 255, 255}, Params: []int{360, 0, 0}},
+		{TypeID: ObjectTypeDonut, Visible: true, FlipHorizontal: true, X: 3500, Y: 2000, Angle: -45, Scale: 40, Color: color.NRGBA{255, 255, 255, 255}, Params: []int{270, 100, 0}},
+		{TypeID: ObjectTypeLineAoE, Visible: true, X: 2500, Y: 3000, Angle: 20, Scale: 100, Color: color.NRGBA{48, 128, 255, 204}, Params: []int{100, 30, 0}},
+		{TypeID: ObjectTypeLine, Visible: true, X: 500, Y: 3500, Scale: 100, Color: color.NRGBA{255, 48, 48, 255}, Params: []int{1500, 2500, 6}},
+		{TypeID: sprite.ID, Visible: false, X: 100, Y: 100, Scale: 100, Color: color.NRGBA{255, 255, 255, 255}, Params: []int{0, 0, 0}},
+	}}
+
+	var out bytes.Buffer
+	if err := drawSVG(board, assets, testQuadrantTexture(), &out); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join("testdata", "render", "board.svg")
+	if *updateRenders {
+		if err := os.WriteFile(path, out.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v, run with -update to create it", err)
+	}
+	if !bytes.Equal(out.Bytes(), want) {
+		got := filepath.Join(t.TempDir(), "board.svg")
+		os.WriteFile(got, out.Bytes(), 0o644)
+		t.Errorf("SVG differs from %s, render written to %s", path, got)
+	}
+}
+
+func TestDrawMissingBackground(t *testing.T) {
+	board := testBoards()[1]
+	board.Background = 9
+	if _, err := DrawWithOptions(board, DefaultDrawOptions); !errors.Is(err, AssetNotFound) {
+		t.Errorf("draw: got %v, want asset not found", err)
+	}
+	var out bytes.Buffer
+	if err := DrawSVG(board, &out); !errors.Is(err, AssetNotFound) {
+		t.Errorf("draw SVG: got %v, want asset not found", err)
+	}
+}
@@ -166,5 +166,5 @@ func main() {
 	handleError(err)
 	handleError(writeToZip(writer, "assets.json", bytes.NewReader(outputAssetJson)))
 
-	log.Println("Done")
+	log.Println("Done, run go generate from the repository root to update object_types_gen.go")
 }
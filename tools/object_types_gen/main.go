@@ -0,0 +1,181 @@
+/*
+Generate object_types_gen.go from the asset list in assets.zip, run with go generate from the repository root
+after tools/asset_compiler rebuilds assets.zip.
+*/
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"io"
+	"log"
+	"os"
+	"slices"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+const assetsZipPath = "assets.zip"
+const assetJsonPath = "assets.json"
+const outputPath = "object_types_gen.go"
+
+/* Object types drawn without a sprite, their constants, names and categories are written by hand in object_type.go. */
+var specialObjectTypes = []objectType{
+	{ID: 10, Name: "CircleAoE"},
+	{ID: 11, Name: "LineAoE"},
+	{ID: 12, Name: "Line"},
+	{ID: 17, Name: "Donut"},
+	{ID: 100, Name: "Text"},
+}
+
+/*
+Name fragments used to sort sprite objects into categories by their asset name, checked in order. Only used
+here, the resulting category of every sprite object is written out so it can be reviewed in the generated file.
+*/
+var categoryKeywords = []struct {
+	category string
+	keywords []string
+}{
+	{"CategoryWaymark", []string{"Waymark"}},
+	{"CategoryRoleMarker", []string{"Tank", "Healer", "Dps", "DPS", "Melee", "Ranged", "Caster", "Role"}},
+	{"CategoryAoEShape", []string{"AoE", "Aoe", "Donut", "Fan", "Stack", "Spread", "Tower", "Knockback", "Gaze", "Proximity"}},
+	{"CategoryMarker", []string{"Marker", "Attack", "Bind", "Ignore", "Square", "Circle", "Cross", "Triangle"}},
+	{"CategoryText", []string{"Text"}},
+}
+
+type asset struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type objectType struct {
+	ID        int
+	Name      string
+	AssetName string
+	Category  string
+}
+
+var objectTypesTemplate = template.Must(template.New("object_types").Parse(`// Code generated by tools/object_types_gen from assets.zip; DO NOT EDIT.
+
+package strategy_board
+
+/* Sprite object types from the asset list. */
+const (
+{{- range .}}
+	ObjectType{{.Name}} ObjectType = {{.ID}}
+{{- end}}
+)
+
+/* Names of sprite object types, as in the asset list. */
+var spriteObjectTypeNames = map[ObjectType]string{
+{{- range .}}
+	ObjectType{{.Name}}: {{printf "%q" .AssetName}},
+{{- end}}
+}
+
+/* Categories of sprite object types. */
+var spriteObjectTypeCategories = map[ObjectType]ObjectCategory{
+{{- range .}}
+	ObjectType{{.Name}}: {{.Category}},
+{{- end}}
+}
+`))
+
+/* Read asset list from the assets zip archive. */
+func loadAssetList(path string) ([]asset, error) {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	file, err := zr.Open(assetJsonPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+	assets := make([]asset, 0)
+	return assets, json.Unmarshal(data, &assets)
+}
+
+/* Turn an asset name into an exported Go identifier. */
+func objectTypeName(name string) string {
+	var out strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		out.WriteRune(r)
+	}
+	return out.String()
+}
+
+/* Category of sprite object by its asset name. */
+func objectTypeCategory(name string) string {
+	for _, c := range categoryKeywords {
+		for _, keyword := range c.keywords {
+			if strings.Contains(name, keyword) {
+				return c.category
+			}
+		}
+	}
+	return "CategoryOther"
+}
+
+/* Build list of sprite object types from assets, special object types keep their ids and names. */
+func buildObjectTypes(assets []asset) []objectType {
+	taken := slices.Clone(specialObjectTypes)
+	out := make([]objectType, 0, len(assets))
+	for _, asset := range assets {
+		if slices.ContainsFunc(taken, func(t objectType) bool { return t.ID == asset.ID }) {
+			continue
+		}
+		name := objectTypeName(asset.Name)
+		if name == "" || slices.ContainsFunc(taken, func(t objectType) bool { return t.Name == name }) {
+			name = fmt.Sprintf("%s%d", name, asset.ID)
+		}
+		t := objectType{ID: asset.ID, Name: name, AssetName: asset.Name, Category: objectTypeCategory(asset.Name)}
+		taken = append(taken, t)
+		out = append(out, t)
+	}
+	slices.SortFunc(out, func(a, b objectType) int { return a.ID - b.ID })
+	return out
+}
+
+/* Write generated Go file with sprite object type constants, names and categories. */
+func writeObjectTypes(path string, assets []asset) error {
+	var buf bytes.Buffer
+	if err := objectTypesTemplate.Execute(&buf, buildObjectTypes(assets)); err != nil {
+		return err
+	}
+	out, err := format.Source(buf.Bytes())
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, out, 0644)
+}
+
+func main() {
+	log.Printf("Read asset list from %s", assetsZipPath)
+	assets, err := loadAssetList(assetsZipPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Write object types to %s", outputPath)
+	if err := writeObjectTypes(outputPath, assets); err != nil {
+		log.Fatal(err)
+	}
+}
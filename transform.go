@@ -0,0 +1,143 @@
+package strategy_board
+
+import (
+	"slices"
+)
+
+/*
+Whole board transforms. Flips are applied the same way Draw applies them: sprites are flipped after
+being rotated while arcs are flipped before, so the two need their angles updated differently.
+*/
+
+/* Whether the object's flips are applied before its rotation. */
+func (o Object) flipsBeforeRotation() bool {
+	return o.TypeID == ObjectTypeCircleAoE || o.TypeID == ObjectTypeDonut
+}
+
+/* Copy of board objects with their own params, so transforms can be applied all or nothing. */
+func (b *Board) cloneObjects() []Object {
+	objects := slices.Clone(b.Objects)
+	for i := range objects {
+		objects[i].Params = slices.Clone(objects[i].Params)
+	}
+	return objects
+}
+
+/*
+Check every object's position, and the end of every line, is within the board after a transform. Only what
+transforms move is checked, so boards already breaking other limits can still be moved around.
+*/
+func checkObjectsOnBoard(objects []Object) error {
+	type coordinate struct {
+		field      string
+		value, max int
+	}
+	for i, object := range objects {
+		coordinates := []coordinate{{"x", object.X, BoardWidth}, {"y", object.Y, BoardHeight}}
+		if object.TypeID == ObjectTypeLine && len(object.Params) >= 2 {
+			coordinates = append(coordinates, coordinate{"params", object.Params[0], BoardWidth}, coordinate{"params", object.Params[1], BoardHeight})
+		}
+		for _, c := range coordinates {
+			if c.value < 0 || c.value > c.max {
+				return &ValidationError{Object: i, Field: c.field, Value: c.value, Err: ValueRangeError}
+			}
+		}
+	}
+	return nil
+}
+
+/* Mirror board left to right, or top to bottom when horizontal is false. */
+func (b *Board) Mirror(horizontal bool) {
+	for i := range b.Objects {
+		object := &b.Objects[i]
+		if horizontal {
+			object.X = BoardWidth - object.X
+		} else {
+			object.Y = BoardHeight - object.Y
+		}
+		switch object.TypeID {
+		case ObjectTypeText:
+			// text is never drawn mirrored
+		case ObjectTypeLine:
+			if len(object.Params) >= 2 {
+				if horizontal {
+					object.Params[0] = BoardWidth - object.Params[0]
+				} else {
+					object.Params[1] = BoardHeight - object.Params[1]
+				}
+			}
+		case ObjectTypeLineAoE:
+			// rectangles are symmetric, mirroring only changes which way they lean
+			object.Angle = normalizeAngle(-object.Angle)
+		default:
+			if object.flipsBeforeRotation() {
+				object.Angle = normalizeAngle(-object.Angle)
+			}
+			if horizontal {
+				object.FlipHorizontal = !object.FlipHorizontal
+			} else {
+				object.FlipVertical = !object.FlipVertical
+			}
+		}
+	}
+}
+
+/* Rotate board clockwise about its center by a number of quarter turns, fails if an object would end up off the board. */
+func (b *Board) Rotate(quarterTurns int) error {
+	quarterTurns = ((quarterTurns % 4) + 4) % 4
+	if quarterTurns == 0 {
+		return nil
+	}
+	rotatePoint := func(x int, y int) (int, int) {
+		// double coordinates so the center of the board stays on whole units
+		dx, dy := 2*x-BoardWidth, 2*y-BoardHeight
+		for range quarterTurns {
+			dx, dy = -dy, dx
+		}
+		return (dx + BoardWidth) / 2, (dy + BoardHeight) / 2
+	}
+	objects := b.cloneObjects()
+	for i := range objects {
+		object := &objects[i]
+		object.X, object.Y = rotatePoint(object.X, object.Y)
+		switch object.TypeID {
+		case ObjectTypeText:
+			// text is always drawn upright
+		case ObjectTypeLine:
+			if len(object.Params) >= 2 {
+				object.Params[0], object.Params[1] = rotatePoint(object.Params[0], object.Params[1])
+			}
+		default:
+			turn := 90 * quarterTurns
+			// rotating a sprite flipped along one axis turns it the other way
+			if !object.flipsBeforeRotation() && object.FlipHorizontal != object.FlipVertical {
+				turn = -turn
+			}
+			object.Angle = normalizeAngle(object.Angle + turn)
+		}
+	}
+	if err := checkObjectsOnBoard(objects); err != nil {
+		return err
+	}
+	b.Objects = objects
+	return nil
+}
+
+/* Move every object by native board units, fails if an object would end up off the board. */
+func (b *Board) Translate(dx int, dy int) error {
+	objects := b.cloneObjects()
+	for i := range objects {
+		object := &objects[i]
+		object.X += dx
+		object.Y += dy
+		if object.TypeID == ObjectTypeLine && len(object.Params) >= 2 {
+			object.Params[0] += dx
+			object.Params[1] += dy
+		}
+	}
+	if err := checkObjectsOnBoard(objects); err != nil {
+		return err
+	}
+	b.Objects = objects
+	return nil
+}
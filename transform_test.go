@@ -0,0 +1,48 @@
+package strategy_board
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestTranslateChecksPositions(t *testing.T) {
+	board := testBoards()[1]
+	// limits a transform doesn't change don't stop it
+	board.Objects[0].Text = strings.Repeat("a", MaxTextLength+1)
+	board.Objects[1].Scale = MaxObjectScale + 1
+	if err := board.Translate(10, 10); err != nil {
+		t.Fatalf("got %v, want no error", err)
+	}
+	if board.Objects[0].X != 2570 || board.Objects[0].Y != 510 {
+		t.Errorf("got text at %d,%d, want 2570,510", board.Objects[0].X, board.Objects[0].Y)
+	}
+
+	// moving an object off the board fails and leaves the board unchanged
+	before := board.cloneObjects()
+	var verr *ValidationError
+	if err := board.Translate(0, BoardHeight); !errors.As(err, &verr) || verr.Field != "y" {
+		t.Errorf("got %v, want y out of range", err)
+	}
+	if board.Objects[0].Y != before[0].Y {
+		t.Errorf("got text y %d after failed translate, want %d", board.Objects[0].Y, before[0].Y)
+	}
+
+	// so does moving a line's end off the board, though its start stays on
+	line := Board{Background: 1, Objects: []Object{testBoards()[1].Objects[4]}}
+	dx := BoardWidth - line.Objects[0].X
+	if err := line.Translate(dx, 0); !errors.As(err, &verr) || verr.Field != "params" {
+		t.Errorf("got %v, want line end out of range", err)
+	}
+}
+
+func TestRotateChecksPositions(t *testing.T) {
+	board := testBoards()[1]
+	board.Objects[0].Text = strings.Repeat("a", MaxTextLength+1)
+	if err := board.Rotate(2); err != nil {
+		t.Fatalf("got %v, want no error", err)
+	}
+	if x, y := board.Objects[0].X, board.Objects[0].Y; x != BoardWidth-2560 || y != BoardHeight-500 {
+		t.Errorf("got text at %d,%d after half turn, want %d,%d", x, y, BoardWidth-2560, BoardHeight-500)
+	}
+}